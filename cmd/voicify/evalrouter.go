@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/internal/transcriptionrouter"
+	"github.com/dooshek/voicify/internal/types"
+)
+
+// tokenPrice is a model price in USD per million tokens
+type tokenPrice struct {
+	input  float64
+	output float64
+}
+
+// modelPrices are list prices of common router models, used for the cost
+// estimate when --input-price and --output-price are not set
+var modelPrices = map[string]tokenPrice{
+	"gpt-4o-mini":  {input: 0.15, output: 0.6},
+	"gpt-4o":       {input: 2.5, output: 10},
+	"gpt-4.1-nano": {input: 0.1, output: 0.4},
+	"gpt-4.1-mini": {input: 0.4, output: 1.6},
+	"gpt-4.1":      {input: 2, output: 8},
+	// Groq models
+	"llama-3.1-8b-instant":    {input: 0.05, output: 0.08},
+	"llama-3.3-70b-versatile": {input: 0.59, output: 0.79},
+	"gemma2-9b-it":            {input: 0.2, output: 0.2},
+}
+
+// runEvalRouterCommand measures how well the router LLM handles a labeled
+// dataset. It only asks the LLM; no action is executed.
+func runEvalRouterCommand(args []string) error {
+	fs := flag.NewFlagSet("eval-router", flag.ContinueOnError)
+	provider := fs.String("provider", "", "Router provider to evaluate instead of llm.router.provider")
+	model := fs.String("model", "", "Router model to evaluate instead of llm.router.model")
+	fake := fs.Bool("fake", false, "Answer with each case's fake_response or its labels instead of calling an API")
+	inputPrice := fs.Float64("input-price", 0, "USD per million prompt tokens, for the cost estimate (default: list price of known models)")
+	outputPrice := fs.Float64("output-price", 0, "USD per million completion tokens, for the cost estimate (default: list price of known models)")
+	minAccuracy := fs.Float64("min-accuracy", 0, "Fail when the action accuracy is below this share, e.g. 0.9")
+	verbose := fs.Bool("verbose", false, "Print every case, not only the wrong ones")
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "USAGE:\n")
+		fmt.Fprintf(out, "  voicify eval-router [OPTIONS] DATASET\n\n")
+		fmt.Fprintf(out, "Runs the labeled utterances of DATASET (.yaml, .yml or .jsonl) through the router LLM\n")
+		fmt.Fprintf(out, "and reports accuracy, a confusion matrix, latency and cost. Each case has utterance,\n")
+		fmt.Fprintf(out, "expected_action and optionally expected_text, window_title, window_app and fake_response.\n\n")
+		fmt.Fprintf(out, "OPTIONS:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one dataset file")
+	}
+
+	cases, err := transcriptionrouter.LoadEvalCases(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	state.Get().UpdateConfig(func(cfg *types.Config) {
+		if *provider != "" {
+			cfg.LLM.Router.Provider = *provider
+		}
+		if *model != "" {
+			cfg.LLM.Router.Model = *model
+		}
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts := transcriptionrouter.EvalOptions{
+		OnResult: func(index int, res transcriptionrouter.EvalResult) {
+			printEvalResult(os.Stdout, index, len(cases), res, *verbose)
+		},
+	}
+	if *fake {
+		opts.Provider = transcriptionrouter.NewEvalFakeProvider(cases)
+		opts.ProviderName = "fake"
+	}
+
+	fmt.Printf("== Router evaluation: %s (%d cases) ==\n", fs.Arg(0), len(cases))
+	report, err := transcriptionrouter.Evaluate(ctx, cases, opts)
+	if err != nil && report == nil {
+		return err
+	}
+	printEvalReport(os.Stdout, report, *inputPrice, *outputPrice)
+	if err != nil {
+		return fmt.Errorf("evaluation stopped after %d cases: %w", len(report.Results), err)
+	}
+
+	if accuracy := report.Accuracy(); accuracy < *minAccuracy {
+		return fmt.Errorf("accuracy %.1f%% is below --min-accuracy %.1f%%", accuracy*100, *minAccuracy*100)
+	}
+	return nil
+}
+
+// printEvalResult prints a case that went wrong, or any case when verbose
+func printEvalResult(out io.Writer, index, total int, res transcriptionrouter.EvalResult, verbose bool) {
+	textWrong := res.Case.ExpectedText != nil && !res.TextCorrect()
+	if !verbose && res.ActionCorrect() && !textWrong {
+		return
+	}
+
+	mark := "ok  "
+	if !res.ActionCorrect() || textWrong {
+		mark = "FAIL"
+	}
+	fmt.Fprintf(out, "%s %d/%d %q\n", mark, index+1, total, res.Case.Utterance)
+	if res.Err != nil {
+		fmt.Fprintf(out, "     error:  %v\n", res.Err)
+		return
+	}
+	fmt.Fprintf(out, "     action: %s (expected %s, confidence %s)\n", res.Action, res.Case.ExpectedAction, formatConfidence(res.Confidence))
+	if res.Case.ExpectedText != nil {
+		fmt.Fprintf(out, "     text:   %q (expected %q)\n", res.Text, *res.Case.ExpectedText)
+	}
+}
+
+// printEvalReport prints the summary and the confusion matrix
+func printEvalReport(out io.Writer, report *transcriptionrouter.EvalReport, inputPrice, outputPrice float64) {
+	total := len(report.Results)
+	correct := 0
+	for _, res := range report.Results {
+		if res.ActionCorrect() {
+			correct++
+		}
+	}
+
+	fmt.Fprintf(out, "\n== Summary ==\n")
+	fmt.Fprintf(out, "provider:      %s\n", report.Provider)
+	if report.Model == "" {
+		fmt.Fprintf(out, "model:         (not set)\n")
+	} else {
+		fmt.Fprintf(out, "model:         %s\n", report.Model)
+	}
+	fmt.Fprintf(out, "accuracy:      %d/%d (%.1f%%)\n", correct, total, report.Accuracy()*100)
+	if textAccuracy, checked := report.TextAccuracy(); checked > 0 {
+		fmt.Fprintf(out, "text accuracy: %.1f%% of %d cases with expected_text\n", textAccuracy*100, checked)
+	}
+	mean, p50, p95, slowest := report.Latency()
+	fmt.Fprintf(out, "latency:       mean %s, p50 %s, p95 %s, max %s\n",
+		mean.Round(time.Millisecond), p50.Round(time.Millisecond), p95.Round(time.Millisecond), slowest.Round(time.Millisecond))
+	usage := report.Usage()
+	fmt.Fprintf(out, "tokens:        %d prompt, %d completion\n", usage.PromptTokens, usage.CompletionTokens)
+	price, source := tokenPrice{input: inputPrice, output: outputPrice}, ""
+	if inputPrice == 0 && outputPrice == 0 {
+		if known, ok := modelPrices[strings.ToLower(report.Model)]; ok {
+			price, source = known, " at list price"
+		}
+	}
+	if price.input > 0 || price.output > 0 {
+		cost := report.Cost(price.input, price.output)
+		fmt.Fprintf(out, "cost:          $%.4f ($%.6f per utterance)%s\n", cost, cost/float64(max(total, 1)), source)
+	} else {
+		fmt.Fprintf(out, "cost:          n/a (unknown model price, set --input-price and --output-price)\n")
+	}
+
+	labels, counts := report.Confusion()
+	fmt.Fprintf(out, "\n== Confusion matrix (rows: expected, columns: chosen) ==\n")
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "\t%s\t\n", strings.Join(labels, "\t"))
+	for _, expected := range labels {
+		row, ok := counts[expected]
+		if !ok {
+			continue
+		}
+		cells := make([]string, len(labels))
+		for i, actual := range labels {
+			cells[i] = fmt.Sprint(row[actual])
+		}
+		fmt.Fprintf(tw, "%s\t%s\t\n", expected, strings.Join(cells, "\t"))
+	}
+	tw.Flush()
+}
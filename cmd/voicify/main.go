@@ -17,7 +17,9 @@ import (
 	"github.com/dooshek/voicify/internal/logger"
 	"github.com/dooshek/voicify/internal/notification"
 	"github.com/dooshek/voicify/internal/plugin/linear"
+	"github.com/dooshek/voicify/internal/redact"
 	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/internal/transcriptionrouter"
 	"github.com/dooshek/voicify/internal/tts"
 	"github.com/dooshek/voicify/internal/types"
 )
@@ -29,10 +31,14 @@ func init() {
 		fmt.Fprintf(out, "Voicify - Voice-controlled text automation\n\n")
 		fmt.Fprintf(out, "USAGE:\n")
 		fmt.Fprintf(out, "  voicify [OPTIONS]\n")
+		fmt.Fprintf(out, "  voicify [OPTIONS] route [--execute] [--audio FILE] [TEXT...]\n")
+		fmt.Fprintf(out, "  voicify [OPTIONS] eval-router [--provider P] [--model M] [--fake] DATASET\n")
 		fmt.Fprintf(out, "\n")
 
 		fmt.Fprintf(out, "COMMANDS:\n")
 		fmt.Fprintf(out, "  (default)    Start voice recording with keyboard monitoring\n")
+		fmt.Fprintf(out, "  route        Explain how a transcription would be routed, without executing it\n")
+		fmt.Fprintf(out, "  eval-router  Measure router accuracy, latency and cost on a labeled dataset\n")
 		fmt.Fprintf(out, "\n")
 
 		fmt.Fprintf(out, "OPTIONS:\n")
@@ -54,6 +60,9 @@ func init() {
 		fmt.Fprintf(out, "  voicify --daemon                        Start D-Bus daemon (for GNOME extension)\n")
 		fmt.Fprintf(out, "  voicify --wizard                        Run configuration wizard\n")
 		fmt.Fprintf(out, "  voicify --log-level debug               Start with debug logging\n")
+		fmt.Fprintf(out, "  voicify route \"dodaj zadanie\"           Show which actions would handle the text\n")
+		fmt.Fprintf(out, "  voicify route --audio note.wav          Transcribe a file and show its routing\n")
+		fmt.Fprintf(out, "  voicify eval-router cases.yaml          Measure router accuracy on a labeled dataset\n")
 		fmt.Fprintf(out, "\n")
 	}
 }
@@ -141,6 +150,29 @@ func main() {
 	// Initialize global state with the entire config
 	state.Init(cfg)
 
+	// Mask personal data and secrets in logs
+	if redactor, err := redact.New(cfg.Redaction); err != nil {
+		logger.Warnf("Invalid redaction config: %v", err)
+	} else if redactor != nil && !cfg.Redaction.KeepLogs {
+		logger.SetRedactor(redactor.Scrub)
+	}
+
+	// Subcommands run once and exit, without the daemon or keyboard monitor
+	if flag.Arg(0) == "route" {
+		if err := runRouteCommand(flag.Args()[1:]); err != nil {
+			logger.Error("Route command failed", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if flag.Arg(0) == "eval-router" {
+		if err := runEvalRouterCommand(flag.Args()[1:]); err != nil {
+			logger.Error("Router evaluation failed", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Initialize TTS manager if configuration is available
 	var ttsManager *tts.Manager
 	if cfg.LLM.Keys.OpenAIKey != "" {
@@ -186,12 +218,12 @@ func main() {
 		startMessage = "D-Bus daemon"
 	} else {
 		// Keyboard monitoring mode
-		monitor, err = keyboard.CreateMonitor(state.Get().Config.RecordKey)
+		monitor, err = keyboard.CreateMonitor(state.Get().GetConfig().RecordKey)
 		if err != nil {
 			logger.Error("Failed to create keyboard monitor", err)
 			os.Exit(1)
 		}
-		startMessage = formatKeyCombo(state.Get().Config.RecordKey)
+		startMessage = formatKeyCombo(state.Get().GetConfig().RecordKey)
 	}
 
 	// Initialize fileops
@@ -249,6 +281,9 @@ func main() {
 		logger.Infof("Received signal %v, shutting down...", sig)
 		cancel() // Cancel context
 
+		// Stop actions still running so they cannot block the shutdown
+		transcriptionrouter.ShutdownRouting()
+
 		// Clean up based on mode
 		if *daemonMode && dbusServer != nil {
 			dbusServer.Stop()
@@ -267,6 +302,19 @@ func main() {
 		os.Exit(0)
 	}()
 
+	// SIGHUP and edits of the config file rebuild the router without a restart
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for range hupChan {
+			logger.Info("Received SIGHUP, reloading configuration...")
+			if err := transcriptionrouter.Reload(); err != nil {
+				logger.Error("Reload failed", err)
+			}
+		}
+	}()
+	go transcriptionrouter.WatchConfig(ctx)
+
 	// Start service based on mode
 	if *daemonMode {
 		// Start D-Bus server
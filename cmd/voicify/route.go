@@ -0,0 +1,186 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dooshek/voicify/internal/postprocess"
+	"github.com/dooshek/voicify/internal/spelling"
+	"github.com/dooshek/voicify/internal/transcriber"
+	"github.com/dooshek/voicify/internal/transcriptionrouter"
+)
+
+// runRouteCommand explains how the router handles a transcription given as
+// arguments, on stdin or as an audio file. Nothing is executed unless
+// --execute is set.
+func runRouteCommand(args []string) error {
+	fs := flag.NewFlagSet("route", flag.ContinueOnError)
+	execute := fs.Bool("execute", false, "Execute the explained decision without asking the LLM again")
+	audioFile := fs.String("audio", "", "Transcribe an audio file instead of reading text")
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "USAGE:\n")
+		fmt.Fprintf(out, "  voicify route [--execute] [--audio FILE] [TEXT...]\n\n")
+		fmt.Fprintf(out, "Shows which actions would handle TEXT (or stdin) without running them.\n\n")
+		fmt.Fprintf(out, "OPTIONS:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	text, err := routeInput(*audioFile, fs.Args())
+	if err != nil {
+		return err
+	}
+	if text == "" {
+		return fmt.Errorf("no transcription given")
+	}
+
+	router := transcriptionrouter.GetOrCreateGlobalRouter()
+	explanation := router.Explain(text)
+	printExplanation(os.Stdout, explanation)
+
+	if *execute {
+		fmt.Println("\n== Execution ==")
+		if err := router.Execute(explanation); err != nil {
+			return fmt.Errorf("routing failed: %w", err)
+		}
+		fmt.Println("done")
+	}
+	return nil
+}
+
+// routeInput returns the transcription to route. Audio is transcribed and
+// cleaned up the same way as a recording.
+func routeInput(audioFile string, args []string) (string, error) {
+	if audioFile != "" {
+		t, err := transcriber.NewTranscriber()
+		if err != nil {
+			return "", fmt.Errorf("failed to create transcriber: %w", err)
+		}
+		text, err := t.TranscribeFile(audioFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to transcribe %s: %w", audioFile, err)
+		}
+		if !spelling.Requested(text) {
+			text = postprocess.Apply(text)
+		}
+		return strings.TrimSpace(text), nil
+	}
+
+	if len(args) > 0 {
+		return strings.TrimSpace(strings.Join(args, " ")), nil
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// printExplanation prints the routing explanation in plain text
+func printExplanation(out io.Writer, e *transcriptionrouter.Explanation) {
+	fmt.Fprintf(out, "== Transcription ==\n%s\n", e.Transcription)
+	if e.Command {
+		fmt.Fprintf(out, "(explicit command: %s)\n", e.Text)
+	}
+
+	if e.Rule != nil {
+		fmt.Fprintf(out, "\n== Rule ==\n")
+		fmt.Fprintf(out, "%s -> %s with text: %s\n", e.Rule.Name, e.Rule.Action, e.Rule.Text)
+		fmt.Fprintf(out, "(rules skip the other actions and the LLM)\n")
+		return
+	}
+
+	fmt.Fprintf(out, "\n== Non-LLM actions ==\n")
+	if e.Command {
+		fmt.Fprintf(out, "(skipped for explicit commands)\n")
+	}
+	for _, st := range e.Steps {
+		status := "run"
+		if st.Skipped != "" {
+			status = "skip: " + st.Skipped
+		}
+		flags := ""
+		switch {
+		case st.EndsRouting:
+			flags = ", handles the text and ends routing"
+		case st.SkipDefaultAction:
+			flags = ", ends routing when it handles the text"
+		}
+		fmt.Fprintf(out, "  %-12s priority %-4d %s%s\n", st.Action, st.Priority, status, flags)
+	}
+
+	fmt.Fprintf(out, "\n== LLM ==\n")
+	if e.LLMSkipped != "" {
+		fmt.Fprintf(out, "skipped: %s\n", e.LLMSkipped)
+		if e.Command {
+			fmt.Fprintf(out, "(the command would be pasted)\n")
+		}
+		return
+	}
+	fmt.Fprintf(out, "-- prompt (%s) --\n%s\n-- end of prompt --\n", e.PromptSource, e.Prompt)
+	if e.LLMError != nil {
+		fmt.Fprintf(out, "error: %v\n", e.LLMError)
+		return
+	}
+
+	d := e.Decision
+	fmt.Fprintf(out, "action:     %s\n", d.Action)
+	fmt.Fprintf(out, "confidence: %s\n", formatConfidence(d.Confidence))
+	fmt.Fprintf(out, "text:       %s\n", d.Text)
+	fmt.Fprintf(out, "thoughts:   %s\n", d.Thoughts)
+	if d.ArgumentsError != nil {
+		fmt.Fprintf(out, "arguments:  invalid, only the text would be passed: %v\n", d.ArgumentsError)
+	} else if len(d.Arguments) > 0 {
+		names := make([]string, 0, len(d.Arguments))
+		for name := range d.Arguments {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(out, "arguments:\n")
+		for _, name := range names {
+			fmt.Fprintf(out, "  %s = %v\n", name, d.Arguments[name])
+		}
+	}
+	if len(d.Steps) > 1 {
+		fmt.Fprintf(out, "plan:\n")
+		for i, step := range d.Steps {
+			input := ""
+			if step.UsePreviousOutput {
+				input = " (+ previous output)"
+			}
+			fmt.Fprintf(out, "  %d. %s: %s%s\n", i+1, step.Action, step.Text, input)
+		}
+	}
+	for _, alt := range d.Alternatives {
+		fmt.Fprintf(out, "alternative: %s (%.2f)\n", alt.Action, alt.Confidence)
+	}
+	switch {
+	case d.UnknownAction && e.Command:
+		fmt.Fprintf(out, "result:     no action named %s, the command would be pasted\n", d.Action)
+	case d.UnknownAction:
+		fmt.Fprintf(out, "result:     no action named %s, nothing would run\n", d.Action)
+	case d.BelowThreshold:
+		fmt.Fprintf(out, "result:     below min confidence %.2f, would %s instead\n", d.MinConfidence, d.LowConfidence)
+	case d.PlanError != nil:
+		fmt.Fprintf(out, "result:     invalid plan, nothing would run: %v\n", d.PlanError)
+	case len(d.Steps) > 1:
+		fmt.Fprintf(out, "result:     would run the plan, stopping at the first failing step\n")
+	default:
+		fmt.Fprintf(out, "result:     would run %s\n", d.Action)
+	}
+}
+
+func formatConfidence(confidence *float64) string {
+	if confidence == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.2f", *confidence)
+}
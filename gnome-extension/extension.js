@@ -68,9 +68,11 @@ const VoicifyDBusInterface = `
     <method name="StartRealtimeRecording"/>
     <method name="TogglePostTranscriptionAutoPaste"/>
     <method name="TogglePostTranscriptionRouter"/>
+    <method name="ToggleHybridRecording"/>
     <method name="GetStatus">
       <arg name="is_recording" type="b" direction="out"/>
     </method>
+    <method name="StopRealtimeRecording"/>
     <method name="CancelRecording"/>
     <method name="UpdateFocusedWindow">
       <arg name="title" type="s" direction="in"/>
@@ -79,6 +81,20 @@ const VoicifyDBusInterface = `
     <method name="SetAutoPausePlayback">
       <arg name="enabled" type="b" direction="in"/>
     </method>
+    <method name="SetLiveTyping">
+      <arg name="enabled" type="b" direction="in"/>
+    </method>
+    <method name="SetEnterAfterPaste">
+      <arg name="enabled" type="b" direction="in"/>
+    </method>
+    <method name="ToggleSpellingMode">
+      <arg name="enabled" type="b" direction="out"/>
+    </method>
+    <method name="ChooseRoutingCandidate">
+      <arg name="action" type="s" direction="in"/>
+    </method>
+    <method name="UndoLast"/>
+    <method name="RedoLast"/>
     <method name="GetRecordingStats">
       <arg name="stats_json" type="s" direction="out"/>
     </method>
@@ -87,9 +103,11 @@ const VoicifyDBusInterface = `
       <arg name="standard" type="s" direction="in"/>
       <arg name="realtime" type="s" direction="in"/>
     </method>
+    <method name="Reload"/>
     <signal name="RecordingStarted"/>
     <signal name="TranscriptionReady">
       <arg name="text" type="s"/>
+      <arg name="rewritten" type="s"/>
     </signal>
     <signal name="PartialTranscription">
       <arg name="text" type="s"/>
@@ -100,6 +118,7 @@ const VoicifyDBusInterface = `
     <signal name="RecordingError">
       <arg name="error" type="s"/>
     </signal>
+    <signal name="RecordingStopped"/>
     <signal name="RecordingCancelled"/>
     <signal name="InputLevel">
       <arg name="level" type="d"/>
@@ -107,6 +126,26 @@ const VoicifyDBusInterface = `
     <signal name="RequestPaste">
       <arg name="text" type="s"/>
     </signal>
+    <signal name="RequestDelete">
+      <arg name="chars" type="u"/>
+    </signal>
+    <signal name="RoutingAmbiguous">
+      <arg name="transcription" type="s"/>
+      <arg name="candidates" type="a(sd)"/>
+    </signal>
+    <signal name="ActionStepCompleted">
+      <arg name="index" type="u"/>
+      <arg name="total" type="u"/>
+      <arg name="action" type="s"/>
+      <arg name="success" type="b"/>
+      <arg name="output" type="s"/>
+    </signal>
+    <signal name="ActionCompleted">
+      <arg name="report" type="s"/>
+    </signal>
+    <signal name="LiveTypingSkipped">
+      <arg name="text" type="s"/>
+    </signal>
   </interface>
 </node>`;
 
@@ -127,6 +166,7 @@ export default class VoicifyExtension extends Extension {
         this._realtimeAction = null;
         this._postAutoPasteAction = null;
         this._postRouterAction = null;
+        this._hybridAction = null;
         this._acceleratorHandlerId = 0;
         this._state = State.IDLE;
         this._waveWidget = null;
@@ -142,6 +182,7 @@ export default class VoicifyExtension extends Extension {
         this._isRealtimeMode = false;
         this._isPostAutoPaste = false;
         this._isPostRouter = false;
+        this._isHybrid = false;
         this._pendingPaste = false;
         this._debounceMs = 500;
         this._virtualKeyboard = null;
@@ -237,6 +278,12 @@ export default class VoicifyExtension extends Extension {
         this._settingsChangedIds.push(
             this._settings.connect('changed::auto-pause-playback', () => this._syncAutoPausePlayback())
         );
+        this._settingsChangedIds.push(
+            this._settings.connect('changed::live-typing', () => this._syncLiveTyping())
+        );
+        this._settingsChangedIds.push(
+            this._settings.connect('changed::enter-after-paste', () => this._syncEnterAfterPaste())
+        );
         this._settingsChangedIds.push(
             this._settings.connect('changed::transcription-model', () => this._sendTranscriptionModels())
         );
@@ -269,6 +316,12 @@ export default class VoicifyExtension extends Extension {
                 this._updateMenuShortcutLabel('shortcut-post-router');
             })
         );
+        this._settingsChangedIds.push(
+            this._settings.connect('changed::shortcut-hybrid', () => {
+                this._grabShortcut('shortcut-hybrid', '_hybridAction');
+                this._updateMenuShortcutLabel('shortcut-hybrid');
+            })
+        );
 
         this._ensureDBusServiceFile();
 
@@ -282,6 +335,8 @@ export default class VoicifyExtension extends Extension {
 
         this._initDBusProxy();
         this._syncAutoPausePlayback();
+        this._syncLiveTyping();
+        this._syncEnterAfterPaste();
         this._createIndicator();
 
         // Grab shortcuts from settings
@@ -289,6 +344,7 @@ export default class VoicifyExtension extends Extension {
         this._grabShortcut('shortcut-realtime', '_realtimeAction');
         this._grabShortcut('shortcut-post-autopaste', '_postAutoPasteAction');
         this._grabShortcut('shortcut-post-router', '_postRouterAction');
+        this._grabShortcut('shortcut-hybrid', '_hybridAction');
 
         // Single accelerator-activated handler for all shortcuts
         this._acceleratorHandlerId = global.display.connect('accelerator-activated',
@@ -297,6 +353,7 @@ export default class VoicifyExtension extends Extension {
                 else if (action === this._realtimeAction) this._onRealtimeShortcutPressed();
                 else if (action === this._postAutoPasteAction) this._onPostAutoPasteShortcutPressed();
                 else if (action === this._postRouterAction) this._onPostRouterShortcutPressed();
+                else if (action === this._hybridAction) this._onHybridShortcutPressed();
             });
     }
 
@@ -328,6 +385,7 @@ export default class VoicifyExtension extends Extension {
         this._ungrabShortcut('_realtimeAction');
         this._ungrabShortcut('_postAutoPasteAction');
         this._ungrabShortcut('_postRouterAction');
+        this._ungrabShortcut('_hybridAction');
 
         this._destroyDecorations();
         this._destroyTrailBars();
@@ -356,6 +414,7 @@ export default class VoicifyExtension extends Extension {
             this._indicator = null;
         }
         this._icon = null;
+        this._routingChoiceSection = null;
         this._enterAfterPasteItem = null;
         this._virtualKeyboard = null;
     }
@@ -406,6 +465,10 @@ export default class VoicifyExtension extends Extension {
 
         this._indicator.add_child(this._icon);
 
+        // Filled when the router asks which action to run
+        this._routingChoiceSection = new PopupMenu.PopupMenuSection();
+        this._indicator.menu.addMenuItem(this._routingChoiceSection);
+
         // Recording mode menu items with shortcut labels
         this._addModeMenuItem('Realtime', 'shortcut-realtime',
             () => this._onRealtimeShortcutPressed());
@@ -413,6 +476,8 @@ export default class VoicifyExtension extends Extension {
             () => this._onPostAutoPasteShortcutPressed());
         this._addModeMenuItem('Post + router', 'shortcut-post-router',
             () => this._onPostRouterShortcutPressed());
+        this._addModeMenuItem('Hybrid', 'shortcut-hybrid',
+            () => this._onHybridShortcutPressed());
         this._addModeMenuItem('Cancel', 'shortcut-cancel',
             () => this._onCancelShortcutPressed());
 
@@ -429,6 +494,10 @@ export default class VoicifyExtension extends Extension {
 
         this._indicator.menu.addMenuItem(new PopupMenu.PopupSeparatorMenuItem());
 
+        const reloadItem = new PopupMenu.PopupMenuItem('Reload configuration');
+        reloadItem.connect('activate', () => this._reloadConfiguration());
+        this._indicator.menu.addMenuItem(reloadItem);
+
         const settingsItem = new PopupMenu.PopupMenuItem('Settings');
         settingsItem.connect('activate', () => this.openPreferences());
         this._indicator.menu.addMenuItem(settingsItem);
@@ -499,6 +568,20 @@ export default class VoicifyExtension extends Extension {
             .catch(e => console.debug('Voicify: SetAutoPausePlayback failed:', e.message));
     }
 
+    _syncLiveTyping() {
+        if (!this._dbusProxy || !this._settings) return;
+        const enabled = this._settings.get_boolean('live-typing');
+        this._dbusProxy.SetLiveTypingAsync(enabled)
+            .catch(e => console.debug('Voicify: SetLiveTyping failed:', e.message));
+    }
+
+    _syncEnterAfterPaste() {
+        if (!this._dbusProxy || !this._settings) return;
+        const enabled = this._settings.get_boolean('enter-after-paste');
+        this._dbusProxy.SetEnterAfterPasteAsync(enabled)
+            .catch(e => console.debug('Voicify: SetEnterAfterPaste failed:', e.message));
+    }
+
     _applyTheme() {
         const themeId = this._settings
             ? this._settings.get_string('wave-theme')
@@ -912,6 +995,7 @@ export default class VoicifyExtension extends Extension {
                 this._isRealtimeMode = false;
                 this._isPostAutoPaste = false;
                 this._isPostRouter = false;
+                this._isHybrid = false;
                 this._updateIndicator();
                 this._fadeOutAndHide(200);
             })
@@ -921,6 +1005,7 @@ export default class VoicifyExtension extends Extension {
                 this._isRealtimeMode = false;
                 this._isPostAutoPaste = false;
                 this._isPostRouter = false;
+                this._isHybrid = false;
                 this._updateIndicator();
                 this._fadeOutAndHide(200);
             });
@@ -959,6 +1044,17 @@ export default class VoicifyExtension extends Extension {
         }
     }
 
+    _onHybridShortcutPressed() {
+        if (Date.now() - this._lastShortcutTime < this._debounceMs) return;
+        this._lastShortcutTime = Date.now();
+
+        if (this._state === State.IDLE) {
+            this._startHybridRecording();
+        } else if (this._state === State.RECORDING && this._isHybrid) {
+            this._stopHybridRecording();
+        }
+    }
+
     // --- Recording start/stop ---
 
     _startRealtimeRecording() {
@@ -971,6 +1067,7 @@ export default class VoicifyExtension extends Extension {
         this._isRealtimeMode = true;
         this._isPostAutoPaste = false;
         this._isPostRouter = false;
+        this._isHybrid = false;
 
         this._dbusProxy.StartRealtimeRecordingAsync()
             .then(() => console.debug('D-Bus: StartRealtimeRecording called'))
@@ -992,6 +1089,7 @@ export default class VoicifyExtension extends Extension {
         this._isRealtimeMode = false;
         this._isPostAutoPaste = true;
         this._isPostRouter = false;
+        this._isHybrid = false;
 
         this._dbusProxy.TogglePostTranscriptionAutoPasteAsync()
             .then(() => console.debug('D-Bus: TogglePostTranscriptionAutoPaste called'))
@@ -1013,6 +1111,7 @@ export default class VoicifyExtension extends Extension {
         this._isRealtimeMode = false;
         this._isPostAutoPaste = false;
         this._isPostRouter = true;
+        this._isHybrid = false;
 
         this._dbusProxy.TogglePostTranscriptionRouterAsync()
             .then(() => console.debug('D-Bus: TogglePostTranscriptionRouter called'))
@@ -1024,22 +1123,44 @@ export default class VoicifyExtension extends Extension {
             });
     }
 
+    _startHybridRecording() {
+        if (!this._dbusProxy) {
+            console.error('D-Bus proxy not initialized');
+            return;
+        }
+
+        this._updateFocusedWindowInDaemon();
+        this._isRealtimeMode = false;
+        this._isPostAutoPaste = false;
+        this._isPostRouter = false;
+        this._isHybrid = true;
+
+        this._dbusProxy.ToggleHybridRecordingAsync()
+            .then(() => console.debug('D-Bus: ToggleHybridRecording called'))
+            .catch(error => {
+                console.error('D-Bus: Failed to call ToggleHybridRecording:', error);
+                this._state = State.IDLE;
+                this._isHybrid = false;
+                this._updateIndicator();
+            });
+    }
+
     _stopRealtimeRecording() {
         if (!this._dbusProxy) {
             console.error('D-Bus proxy not initialized');
             return;
         }
 
-        this._dbusProxy.CancelRecordingAsync()
+        this._dbusProxy.StopRealtimeRecordingAsync()
             .then(() => {
-                console.debug('D-Bus: CancelRecording called');
+                console.debug('D-Bus: StopRealtimeRecording called');
                 this._state = State.IDLE;
                 this._isRealtimeMode = false;
                 this._updateIndicator();
                 this._fadeOutAndHide(200);
             })
             .catch(error => {
-                console.error('D-Bus: Failed to call CancelRecording:', error);
+                console.error('D-Bus: Failed to call StopRealtimeRecording:', error);
                 this._state = State.IDLE;
                 this._isRealtimeMode = false;
                 this._updateIndicator();
@@ -1091,6 +1212,28 @@ export default class VoicifyExtension extends Extension {
             });
     }
 
+    _stopHybridRecording() {
+        if (!this._dbusProxy) {
+            console.error('D-Bus proxy not initialized');
+            return;
+        }
+
+        this._updateFocusedWindowInDaemon();
+        this._state = State.UPLOADING;
+        this._updateIndicator();
+        this._updateWaveWidget();
+
+        this._dbusProxy.ToggleHybridRecordingAsync()
+            .then(() => console.debug('D-Bus: ToggleHybridRecording called (stop)'))
+            .catch(error => {
+                console.error('D-Bus: Failed to call ToggleHybridRecording:', error);
+                this._state = State.IDLE;
+                this._isHybrid = false;
+                this._updateIndicator();
+                this._fadeOutAndHide(200);
+            });
+    }
+
     // --- D-Bus signal handlers ---
 
     _onTranscriptionReady(text) {
@@ -1098,10 +1241,12 @@ export default class VoicifyExtension extends Extension {
 
         if (this._isRealtimeMode) return;
 
-        if (this._isPostRouter) {
+        if (this._isPostRouter || this._isHybrid) {
+            // The daemon routed the transcription like in post-router mode
             console.debug('Post-router mode - waiting for plugin RequestPaste signal');
             this._state = State.FINISHED;
             this._isPostRouter = false;
+            this._isHybrid = false;
             this._updateIndicator();
             this._startFinishedAnimation();
         } else if (this._isPostAutoPaste) {
@@ -1118,9 +1263,8 @@ export default class VoicifyExtension extends Extension {
     }
 
     _onCompleteTranscription(text) {
-        if (this._isRealtimeMode && text && text.length > 0) {
-            this._injectTextDelta(text + ' ');
-        }
+        // Live typing is done by the daemon through RequestPaste
+        console.debug('Complete transcription:', text);
     }
 
     _onRequestPaste(text) {
@@ -1135,26 +1279,93 @@ export default class VoicifyExtension extends Extension {
         });
     }
 
+    _onRecordingStopped() {
+        // A spoken stop command ends realtime recording without the shortcut
+        if (this._state !== State.RECORDING || !this._isRealtimeMode) return;
+        this._state = State.IDLE;
+        this._isRealtimeMode = false;
+        this._updateIndicator();
+        this._fadeOutAndHide(200);
+    }
+
     _onRecordingCancelled() {
         this._state = State.IDLE;
         this._isRealtimeMode = false;
         this._isPostAutoPaste = false;
         this._isPostRouter = false;
+        this._isHybrid = false;
         this._updateIndicator();
         this._fadeOutAndHide(200);
     }
 
     // --- Text injection ---
 
-    _injectTextDelta(text) {
-        St.Clipboard.get_default().set_text(St.ClipboardType.CLIPBOARD, text);
+    _onRoutingAmbiguous(transcription, candidates) {
+        console.debug('Routing ambiguous:', transcription, JSON.stringify(candidates));
+        if (!this._indicator || !this._routingChoiceSection) return;
 
-        if (this._pasteTimerId) GLib.Source.remove(this._pasteTimerId);
-        this._pasteTimerId = GLib.timeout_add(GLib.PRIORITY_DEFAULT, 100, () => {
-            this._pasteTimerId = null;
-            this._performAutoPaste();
-            return GLib.SOURCE_REMOVE;
-        });
+        // Offer the candidates at the top of the panel menu until one is picked
+        const section = this._routingChoiceSection;
+        section.removeAll();
+
+        const title = new PopupMenu.PopupMenuItem(`Which action? "${transcription}"`, { reactive: false });
+        section.addMenuItem(title);
+
+        const choose = action => {
+            section.removeAll();
+            this._dbusProxy.ChooseRoutingCandidateAsync(action)
+                .catch(e => console.error('Voicify: ChooseRoutingCandidate failed:', e.message));
+        };
+
+        for (const [action, confidence] of candidates) {
+            const item = new PopupMenu.PopupMenuItem(`${action} (${Math.round(confidence * 100)}%)`);
+            item.connect('activate', () => choose(action));
+            section.addMenuItem(item);
+        }
+
+        const pasteItem = new PopupMenu.PopupMenuItem('Just paste');
+        pasteItem.connect('activate', () => choose('default'));
+        section.addMenuItem(pasteItem);
+        section.addMenuItem(new PopupMenu.PopupSeparatorMenuItem());
+
+        this._indicator.menu.open();
+    }
+
+    _onActionStepCompleted(index, total, action, success, output) {
+        // Failures reach the user through ActionCompleted
+        console.log(`Voicify: step ${index}/${total} (${action}) ${success ? 'done' : 'failed'}: ${output}`);
+    }
+
+    _onActionCompleted(payload) {
+        let report;
+        try {
+            report = JSON.parse(payload);
+        } catch (e) {
+            console.error('Voicify: Invalid ActionCompleted report:', e.message);
+            return;
+        }
+
+        // Plain pastes report nothing worth a toast
+        for (const result of report.results ?? []) {
+            if (result.severity === 'info' && !result.message && !result.url)
+                continue;
+            const title = result.severity === 'error' ? `Voicify: ${result.action} failed` : `Voicify: ${result.action}`;
+            const body = [result.message, result.url].filter(Boolean).join('\n');
+            Main.notify(title, body);
+        }
+    }
+
+    _onRequestDelete(chars) {
+        console.debug('RequestDelete:', chars);
+        if (!this._virtualKeyboard) return;
+
+        // Undo of the last insertion - one Backspace per character
+        let t = global.get_current_time();
+        for (let i = 0; i < chars; i++) {
+            this._virtualKeyboard.notify_keyval(t, Clutter.KEY_BackSpace, Clutter.KeyState.PRESSED);
+            this._virtualKeyboard.notify_keyval(t + 5, Clutter.KEY_BackSpace, Clutter.KeyState.RELEASED);
+            t += 10;
+        }
     }
 
     _performAutoPaste() {
@@ -2058,7 +2269,9 @@ export default class VoicifyExtension extends Extension {
         );
 
         this._dbusSignalIds.push(
-            this._dbusProxy.connectSignal('TranscriptionReady', (proxy, sender, [text]) => {
+            this._dbusProxy.connectSignal('TranscriptionReady', (proxy, sender, [text, rewritten]) => {
+                if (rewritten)
+                    console.log(`Transcription rewritten: ${text} -> ${rewritten}`);
                 this._onTranscriptionReady(text);
             })
         );
@@ -2085,6 +2298,12 @@ export default class VoicifyExtension extends Extension {
             })
         );
 
+        this._dbusSignalIds.push(
+            this._dbusProxy.connectSignal('RecordingStopped', () => {
+                this._onRecordingStopped();
+            })
+        );
+
         this._dbusSignalIds.push(
             this._dbusProxy.connectSignal('RecordingCancelled', () => {
                 this._onRecordingCancelled();
@@ -2106,6 +2325,37 @@ export default class VoicifyExtension extends Extension {
             })
         );
 
+        this._dbusSignalIds.push(
+            this._dbusProxy.connectSignal('RequestDelete', (proxy, sender, [chars]) => {
+                this._onRequestDelete(chars);
+            })
+        );
+
+        this._dbusSignalIds.push(
+            this._dbusProxy.connectSignal('RoutingAmbiguous', (proxy, sender, [transcription, candidates]) => {
+                this._onRoutingAmbiguous(transcription, candidates);
+            })
+        );
+
+        this._dbusSignalIds.push(
+            this._dbusProxy.connectSignal('ActionStepCompleted', (proxy, sender, [index, total, action, success, output]) => {
+                this._onActionStepCompleted(index, total, action, success, output);
+            })
+        );
+
+        this._dbusSignalIds.push(
+            this._dbusProxy.connectSignal('ActionCompleted', (proxy, sender, [report]) => {
+                this._onActionCompleted(report);
+            })
+        );
+
+        this._dbusSignalIds.push(
+            this._dbusProxy.connectSignal('LiveTypingSkipped', (proxy, sender, [text]) => {
+                Main.notify('Voicify: focus changed while typing',
+                    `Not typed, copied to the clipboard: ${text}`);
+            })
+        );
+
         // Send initial transcription models to daemon
         this._sendTranscriptionModels();
     }
@@ -2121,6 +2371,13 @@ export default class VoicifyExtension extends Extension {
         }
     }
 
+    _reloadConfiguration() {
+        if (!this._dbusProxy) return;
+        this._dbusProxy.ReloadAsync()
+            .then(() => Main.notify('Voicify', 'Configuration reloaded'))
+            .catch(e => Main.notify('Voicify: reload failed', e.message));
+    }
+
     // --- D-Bus service file ---
 
     _ensureDBusServiceFile() {
@@ -358,6 +358,7 @@ export default class VoicifyPreferences extends ExtensionPreferences {
         _addShortcutRow(shortcutsGroup, 'Realtime', 'shortcut-realtime', settings, window);
         _addShortcutRow(shortcutsGroup, 'Post + auto-paste', 'shortcut-post-autopaste', settings, window);
         _addShortcutRow(shortcutsGroup, 'Post + router', 'shortcut-post-router', settings, window);
+        _addShortcutRow(shortcutsGroup, 'Hybrid', 'shortcut-hybrid', settings, window);
         _addShortcutRow(shortcutsGroup, 'Cancel', 'shortcut-cancel', settings, window);
 
         // === Transcription page (third tab) ===
@@ -467,6 +468,13 @@ export default class VoicifyPreferences extends ExtensionPreferences {
         settings.bind('auto-pause-playback', autoPauseRow, 'active', Gio.SettingsBindFlags.DEFAULT);
         behaviorGroup.add(autoPauseRow);
 
+        const liveTypingRow = new Adw.SwitchRow({
+            title: 'Live Typing',
+            subtitle: 'Type realtime transcription into the focused window as you speak',
+        });
+        settings.bind('live-typing', liveTypingRow, 'active', Gio.SettingsBindFlags.DEFAULT);
+        behaviorGroup.add(liveTypingRow);
+
         // Check playerctl when auto-pause is toggled ON
         autoPauseRow.connect('notify::active', () => {
             if (!autoPauseRow.active) return;
@@ -684,6 +692,13 @@ export default class VoicifyPreferences extends ExtensionPreferences {
         };
 
         const getModelCost = (model, totalSeconds) => {
+            if (model.startsWith('hybrid/')) {
+                // Hybrid recordings run the realtime and the batch model over the same audio
+                const costs = model.slice('hybrid/'.length).split('+')
+                    .map(m => getModelCost(m, totalSeconds));
+                if (costs.some(c => c === null)) return null;
+                return costs.reduce((sum, c) => sum + c, 0);
+            }
             const pricePerMin = MODEL_PRICING[model];
             if (pricePerMin === undefined) return null;
             return (totalSeconds / 60) * pricePerMin;
@@ -0,0 +1,160 @@
+package audio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dooshek/voicify/internal/fileops"
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/transcriber"
+	"github.com/dooshek/voicify/internal/voicecommand"
+)
+
+// HybridRecorder streams audio to the realtime transcriber for live preview
+// while buffering the full recording for a high-accuracy batch pass on stop
+type HybridRecorder struct {
+	realtime    *RealtimeRecorder
+	transcriber *transcriber.Transcriber
+	fileOps     fileops.FileOps
+}
+
+// HybridResult holds the outcome of a hybrid recording
+type HybridResult struct {
+	// Text is the final transcription - batch text, or the preview on fallback
+	Text string
+	// Preview is the text assembled from realtime segments
+	Preview string
+	// UsedFallback is true when the batch pass failed and Preview was used
+	UsedFallback bool
+}
+
+// NewHybridRecorder creates a hybrid recorder on top of an existing realtime recorder
+func NewHybridRecorder(realtime *RealtimeRecorder) (*HybridRecorder, error) {
+	fileOps, err := fileops.NewDefaultFileOps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize file operations: %w", err)
+	}
+
+	batchTranscriber, err := transcriber.NewTranscriber()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize transcriber: %w", err)
+	}
+
+	return &HybridRecorder{
+		realtime:    realtime,
+		transcriber: batchTranscriber,
+		fileOps:     fileOps,
+	}, nil
+}
+
+// Start begins realtime streaming with full audio capture
+func (h *HybridRecorder) Start() error {
+	h.realtime.SetCaptureAudio(true)
+	if err := h.realtime.Start(); err != nil {
+		h.realtime.SetCaptureAudio(false)
+		return err
+	}
+	return nil
+}
+
+// Cancel discards the recording without running the batch pass
+func (h *HybridRecorder) Cancel() {
+	h.realtime.Cancel()
+	h.realtime.SetCaptureAudio(false)
+}
+
+// Stop ends streaming and transcribes the buffered audio with the standard model.
+// Voice commands are applied to the batch text like they were to the preview.
+// The realtime preview is returned as the final text if the batch pass fails.
+func (h *HybridRecorder) Stop() (HybridResult, error) {
+	preview, err := h.realtime.Stop()
+	if err != nil {
+		logger.Warnf("Hybrid: realtime stop failed: %v", err)
+	}
+	pcm := h.realtime.CapturedAudio()
+	h.realtime.SetCaptureAudio(false)
+
+	result := HybridResult{Preview: preview}
+
+	text, err := h.transcribePCM(pcm)
+	if err != nil || strings.TrimSpace(text) == "" {
+		if preview == "" {
+			if err == nil {
+				err = fmt.Errorf("empty transcription")
+			}
+			return result, fmt.Errorf("hybrid batch transcription failed: %w", err)
+		}
+		logger.Warnf("Hybrid: batch transcription unavailable (%v), using realtime preview", err)
+		result.Text = preview
+		result.UsedFallback = true
+		return result, nil
+	}
+
+	result.Text = applyBatchCommands(h.realtime.commandGrammar(), text)
+	return result, nil
+}
+
+// applyBatchCommands executes the voice commands in the batch transcription,
+// which contains them as plain words. The batch pass has no segments, so
+// "cancel that" removes the sentence before it, like "delete last sentence".
+func applyBatchCommands(grammar *voicecommand.Grammar, text string) string {
+	if grammar == nil {
+		return text
+	}
+
+	applied := ""
+	for _, part := range grammar.Parse(text) {
+		switch part.Action {
+		case "":
+			if applied != "" && !strings.HasSuffix(applied, "\n") {
+				applied += " "
+			}
+			applied += part.Text
+		case voicecommand.ActionNewParagraph:
+			applied = strings.TrimRight(applied, " ") + "\n\n"
+		case voicecommand.ActionNewLine:
+			applied = strings.TrimRight(applied, " ") + "\n"
+		case voicecommand.ActionDeleteLastSentence, voicecommand.ActionCancelThat:
+			applied = transcriber.TrimLastSentence(applied)
+		case voicecommand.ActionCancelAll:
+			applied = ""
+		}
+		if part.Action != "" {
+			logger.Debugf("Hybrid: voice command: %s", part.Action)
+		}
+	}
+	return strings.TrimSpace(applied)
+}
+
+// transcribePCM writes captured PCM to a temporary WAV file and transcribes it
+func (h *HybridRecorder) transcribePCM(pcm []byte) (string, error) {
+	if len(pcm) == 0 {
+		return "", fmt.Errorf("no audio captured")
+	}
+
+	wavData, err := convertPCMToWAV(pcm, realtimeChannels, realtimeSampleRate)
+	if err != nil {
+		return "", fmt.Errorf("error converting to WAV: %w", err)
+	}
+
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	wavPath := filepath.Join(h.fileOps.GetRecordingsDir(), fmt.Sprintf("hybrid_%s.wav", timestamp))
+	if err := os.WriteFile(wavPath, wavData, 0o644); err != nil {
+		return "", fmt.Errorf("error writing WAV file: %w", err)
+	}
+	defer os.Remove(wavPath)
+
+	logger.Info("🎙️ Transcribing hybrid recording...")
+	transcriptionStartTime := time.Now()
+	text, err := h.transcriber.TranscribeFile(wavPath)
+	if err != nil {
+		return "", err
+	}
+	logger.Debugf("Hybrid batch transcription took: %d ms", time.Since(transcriptionStartTime).Milliseconds())
+	logger.Infof("📝 Transcription: %s", text)
+
+	return text, nil
+}
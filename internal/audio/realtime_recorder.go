@@ -3,11 +3,13 @@ package audio
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/dooshek/voicify/internal/logger"
 	"github.com/dooshek/voicify/internal/notification"
 	"github.com/dooshek/voicify/internal/transcriber"
+	"github.com/dooshek/voicify/internal/voicecommand"
 	"github.com/gen2brain/malgo"
 )
 
@@ -20,25 +22,55 @@ const (
 	audioChunkBytes    = audioChunkSamples * 2                      // 16-bit = 2 bytes per sample
 )
 
+// Segment is a completed realtime segment with voice commands applied
+type Segment struct {
+	// Text is the segment with command words removed
+	Text string
+	// Retractions are removals from earlier segments requested by voice
+	// commands in this one, in the order they were spoken
+	Retractions []Retraction
+}
+
+// Retraction is text a voice command removed from the end of an earlier segment
+type Retraction struct {
+	Text string
+	// Whole is set when nothing of the segment is left
+	Whole bool
+}
+
 // RealtimeRecorder handles real-time recording and transcription
 type RealtimeRecorder struct {
 	isRecording bool
 	cancelled   bool
-	transcriber *transcriber.RealtimeTranscriber
+	transcriber transcriber.StreamingTranscriber
 	notifier    notification.Notifier
 	mu          sync.Mutex
 
+	// Transcript assembled from realtime segments for the current session
+	transcript *transcriber.Transcript
+
+	// Voice command grammar applied to completed segments (nil disables commands)
+	commands    *voicecommand.Grammar
+	stopRequest chan struct{}
+
 	// Channels for streaming results
 	partialChan  chan string
-	completeChan chan string
+	completeChan chan Segment
 	errorChan    chan error
 
 	// Audio level tracking
 	level *LevelProcessor
 
+	// Full-session PCM capture, used by hybrid mode for a batch pass on stop
+	captureAudio  bool
+	capturedAudio []byte
+	audioMu       sync.Mutex
+
 	// Context for cancellation
 	ctx    context.Context
 	cancel context.CancelFunc
+	// forwardDone is closed when transcript forwarding has stopped
+	forwardDone chan struct{}
 }
 
 // NewRealtimeRecorder creates a new real-time recorder
@@ -48,7 +80,7 @@ func NewRealtimeRecorder() (*RealtimeRecorder, error) {
 
 // NewRealtimeRecorderWithNotifier creates a real-time recorder with custom notifier
 func NewRealtimeRecorderWithNotifier(notifier notification.Notifier) (*RealtimeRecorder, error) {
-	transcriber, err := transcriber.NewRealtimeTranscriber()
+	streamingTranscriber, err := transcriber.NewStreamingTranscriber()
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize realtime transcriber: %w", err)
 	}
@@ -56,11 +88,13 @@ func NewRealtimeRecorderWithNotifier(notifier notification.Notifier) (*RealtimeR
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &RealtimeRecorder{
-		transcriber:  transcriber,
+		transcriber:  streamingTranscriber,
 		notifier:     notifier,
+		transcript:   transcriber.NewTranscript(),
 		partialChan:  make(chan string, 50),
-		completeChan: make(chan string, 10),
+		completeChan: make(chan Segment, 10),
 		errorChan:    make(chan error, 10),
+		stopRequest:  make(chan struct{}, 1),
 		level:        NewLevelProcessor(),
 		ctx:          ctx,
 		cancel:       cancel,
@@ -85,6 +119,16 @@ func (rr *RealtimeRecorder) Start() error {
 
 	// Recreate context for this recording session
 	rr.ctx, rr.cancel = context.WithCancel(context.Background())
+	rr.transcript.Reset()
+	select {
+	case <-rr.stopRequest:
+		// Drop a stop request left over from the previous session
+	default:
+	}
+	rr.dropStaleSegments()
+	rr.audioMu.Lock()
+	rr.capturedAudio = nil
+	rr.audioMu.Unlock()
 
 	// Start the transcriber WebSocket connection
 	if err := rr.transcriber.Start(); err != nil {
@@ -98,7 +142,8 @@ func (rr *RealtimeRecorder) Start() error {
 	go rr.recordAndStream()
 
 	// Start transcript forwarding
-	go rr.forwardTranscripts()
+	rr.forwardDone = make(chan struct{})
+	go rr.forwardTranscripts(rr.ctx, rr.forwardDone)
 
 	logger.Infof("🎙️ Real-time recording started...")
 	rr.notifier.NotifyRecordingStarted()
@@ -110,21 +155,24 @@ func (rr *RealtimeRecorder) Start() error {
 // Stop ends the recording and returns accumulated transcription
 func (rr *RealtimeRecorder) Stop() (string, error) {
 	rr.mu.Lock()
-	defer rr.mu.Unlock()
-
 	if !rr.isRecording {
+		rr.mu.Unlock()
 		return "", nil
 	}
 
 	rr.isRecording = false
-	rr.cancel() // Cancel context to stop goroutines
-	rr.transcriber.Stop()
 	rr.notifier.PlayStopBeep()
+	cancel, forwardDone := rr.cancel, rr.forwardDone
+	rr.mu.Unlock()
+
+	// Segments of the last words arrive while the transcriber drains, so
+	// forwarding keeps running until it has closed the session
+	rr.transcriber.Stop()
+	cancel() // Cancel context to stop goroutines
+	<-forwardDone
+	rr.completePending()
 
-	// TODO: Accumulate and return final transcription
-	// For now, just return empty - the real-time functionality
-	// sends transcripts through channels, not return values
-	return "", nil
+	return rr.transcript.Text(), nil
 }
 
 // Cancel cancels the current recording
@@ -140,17 +188,18 @@ func (rr *RealtimeRecorder) Cancel() {
 	rr.isRecording = false
 	rr.cancelled = true
 	rr.cancel()
-	rr.transcriber.Stop()
+	rr.transcriber.Cancel()
 	rr.notifier.PlayStopBeep()
 }
 
-// PartialChan returns channel for partial transcription results
+// PartialChan returns channel with the text of the segment currently being spoken
 func (rr *RealtimeRecorder) PartialChan() <-chan string {
 	return rr.partialChan
 }
 
-// CompleteChan returns channel for complete transcription results
-func (rr *RealtimeRecorder) CompleteChan() <-chan string {
+// CompleteChan returns channel with each completed segment. Retractions travel
+// with the segment so they are applied before its text.
+func (rr *RealtimeRecorder) CompleteChan() <-chan Segment {
 	return rr.completeChan
 }
 
@@ -164,12 +213,46 @@ func (rr *RealtimeRecorder) LevelChan() <-chan float64 {
 	return rr.level.LevelChan
 }
 
+// SetCommandGrammar sets the voice command grammar applied to completed segments
+func (rr *RealtimeRecorder) SetCommandGrammar(grammar *voicecommand.Grammar) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.commands = grammar
+}
+
+// commandGrammar returns the voice command grammar, nil when commands are disabled
+func (rr *RealtimeRecorder) commandGrammar() *voicecommand.Grammar {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	return rr.commands
+}
+
+// StopRequestChan signals when the user asked to stop recording by voice
+func (rr *RealtimeRecorder) StopRequestChan() <-chan struct{} {
+	return rr.stopRequest
+}
+
+// SetCaptureAudio enables buffering of the whole session's PCM audio
+func (rr *RealtimeRecorder) SetCaptureAudio(enabled bool) {
+	rr.audioMu.Lock()
+	defer rr.audioMu.Unlock()
+	rr.captureAudio = enabled
+	rr.capturedAudio = nil
+}
+
+// CapturedAudio returns the PCM16 mono audio buffered since Start
+func (rr *RealtimeRecorder) CapturedAudio() []byte {
+	rr.audioMu.Lock()
+	defer rr.audioMu.Unlock()
+	return rr.capturedAudio
+}
+
 // SetRealtimeModel sets the transcription model for the next realtime session
 func (rr *RealtimeRecorder) SetRealtimeModel(model string) {
 	rr.transcriber.SetModel(model)
 }
 
-// recordAndStream captures audio and streams it to OpenAI WebSocket
+// recordAndStream captures audio and streams it to the streaming transcriber
 func (rr *RealtimeRecorder) recordAndStream() {
 	ctx, err := malgo.InitContext(nil, malgo.ContextConfig{}, nil)
 	if err != nil {
@@ -197,6 +280,13 @@ func (rr *RealtimeRecorder) recordAndStream() {
 			// Add to buffer
 			audioBuffer = append(audioBuffer, inputBuffer...)
 
+			// Keep full session audio when capture is enabled
+			rr.audioMu.Lock()
+			if rr.captureAudio {
+				rr.capturedAudio = append(rr.capturedAudio, inputBuffer...)
+			}
+			rr.audioMu.Unlock()
+
 			// Process audio levels
 			rr.level.Process(inputBuffer)
 
@@ -229,23 +319,26 @@ func (rr *RealtimeRecorder) recordAndStream() {
 	<-rr.ctx.Done()
 }
 
-// forwardTranscripts forwards transcription results from transcriber to recorder channels
-func (rr *RealtimeRecorder) forwardTranscripts() {
+// forwardTranscripts applies transcriber updates to the transcript and forwards
+// segment text to recorder channels
+func (rr *RealtimeRecorder) forwardTranscripts(ctx context.Context, done chan struct{}) {
+	defer close(done)
 	for {
 		select {
-		case <-rr.ctx.Done():
+		case <-ctx.Done():
 			return
-		case partial := <-rr.transcriber.PartialChan():
+		case ev := <-rr.transcriber.PartialChan():
+			segmentText := rr.transcript.ApplyDelta(ev)
 			select {
-			case rr.partialChan <- partial:
+			case rr.partialChan <- segmentText:
 			default:
-				// Drop if channel is full
+				// Drop if channel is full - next update carries the whole segment
 			}
-		case complete := <-rr.transcriber.TranscriptChan():
+		case ev := <-rr.transcriber.TranscriptChan():
 			select {
-			case rr.completeChan <- complete:
-			default:
-				// Drop if channel is full
+			case rr.completeChan <- rr.completeSegment(ev):
+			case <-ctx.Done():
+				return
 			}
 		case err := <-rr.transcriber.ErrorChan():
 			select {
@@ -256,3 +349,93 @@ func (rr *RealtimeRecorder) forwardTranscripts() {
 		}
 	}
 }
+
+// dropStaleSegments empties the segment channels of segments of the previous
+// session that no one read, so they are not forwarded as part of this one
+func (rr *RealtimeRecorder) dropStaleSegments() {
+	for {
+		select {
+		case <-rr.partialChan:
+		case <-rr.completeChan:
+		default:
+			return
+		}
+	}
+}
+
+// completeSegment applies a completed transcriber segment to the transcript
+func (rr *RealtimeRecorder) completeSegment(ev transcriber.TranscriptEvent) Segment {
+	complete := rr.applyCommands(ev)
+	ev.Text = complete.Text
+	complete.Text = rr.transcript.Complete(ev)
+	return complete
+}
+
+// completePending adds segments that completed after forwarding stopped to
+// the transcript
+func (rr *RealtimeRecorder) completePending() {
+	for {
+		select {
+		case ev := <-rr.transcriber.TranscriptChan():
+			rr.completeSegment(ev)
+		default:
+			return
+		}
+	}
+}
+
+// applyCommands executes voice commands spoken in a completed segment and
+// returns the segment with command words removed
+func (rr *RealtimeRecorder) applyCommands(ev transcriber.TranscriptEvent) Segment {
+	grammar := rr.commandGrammar()
+	if grammar == nil {
+		return Segment{Text: ev.Text}
+	}
+
+	var seg Segment
+	text := ""
+	for _, part := range grammar.Parse(ev.Text) {
+		switch part.Action {
+		case "":
+			if text != "" && !strings.HasSuffix(text, "\n") {
+				text += " "
+			}
+			text += part.Text
+		case voicecommand.ActionNewParagraph:
+			text = strings.TrimRight(text, " ") + "\n\n"
+		case voicecommand.ActionNewLine:
+			text = strings.TrimRight(text, " ") + "\n"
+		case voicecommand.ActionDeleteLastSentence:
+			if strings.TrimSpace(text) != "" {
+				text = transcriber.TrimLastSentence(text)
+			} else if removed, whole := rr.transcript.DeleteLastSentence(ev.ItemID); removed != "" {
+				seg.Retractions = append(seg.Retractions, Retraction{Text: removed, Whole: whole})
+			}
+		case voicecommand.ActionCancelThat:
+			// Cancels what was said in this segment, or the previous segment
+			// when the command is all there is
+			if strings.TrimSpace(text) != "" {
+				text = ""
+			} else if removed := rr.transcript.DropLastSegment(ev.ItemID); removed != "" {
+				seg.Retractions = append(seg.Retractions, Retraction{Text: removed, Whole: true})
+			}
+		case voicecommand.ActionCancelAll:
+			// Cancels everything said so far, the most recent segment first
+			text = ""
+			for removed := rr.transcript.DropLastSegment(ev.ItemID); removed != ""; removed = rr.transcript.DropLastSegment(ev.ItemID) {
+				seg.Retractions = append(seg.Retractions, Retraction{Text: removed, Whole: true})
+			}
+		case voicecommand.ActionStopRecording:
+			select {
+			case rr.stopRequest <- struct{}{}:
+			default:
+				// Stop already requested
+			}
+		}
+		if part.Action != "" {
+			logger.Debugf("Voice command: %s", part.Action)
+		}
+	}
+	seg.Text = text
+	return seg
+}
@@ -48,6 +48,29 @@ func CopyToClipboard(text string) error {
 	return cmd.Run()
 }
 
+// ReadClipboard returns the current text contents of the clipboard.
+func ReadClipboard() (string, error) {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("pbpaste")
+	case "linux":
+		if _, err := exec.LookPath("xclip"); err != nil {
+			return "", errors.New("xclip is not installed")
+		}
+		cmd = exec.Command("xclip", "-selection", "clipboard", "-o")
+	default:
+		return "", errors.New("clipboard is not supported on " + runtime.GOOS)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
 // PasteWithReturn simulates pasting the given text and pressing the return key.
 func PasteWithReturn(text string) error {
 	// Copy text to clipboard
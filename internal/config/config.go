@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/dooshek/voicify/internal/fileops"
 	"github.com/dooshek/voicify/internal/logger"
@@ -39,6 +40,15 @@ func LoadConfig() (*types.Config, error) {
 	return &config, nil
 }
 
+// FilePath returns the path of the config file, whether it exists or not
+func FilePath() (string, error) {
+	fileOps, err := fileops.NewDefaultFileOps()
+	if err != nil {
+		return "", fmt.Errorf("failed to initialize file operations: %w", err)
+	}
+	return filepath.Join(fileOps.GetConfigDir(), configFilename), nil
+}
+
 func SaveConfig(config *types.Config) error {
 	fileOps, err := fileops.NewDefaultFileOps()
 	if err != nil {
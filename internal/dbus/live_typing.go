@@ -0,0 +1,216 @@
+package dbus
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dooshek/voicify/internal/audio"
+	"github.com/dooshek/voicify/internal/clipboard"
+	"github.com/dooshek/voicify/internal/history"
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/rewrite"
+)
+
+// liveSegment is a completed realtime segment of the live typing session
+type liveSegment struct {
+	// text is the segment as transcribed
+	text string
+	// typed is the text inserted into the window; empty when the segment was skipped
+	typed string
+	// rewritten is set when a rewrite profile changed the typed text
+	rewritten bool
+}
+
+// typeLiveSegment inserts a completed realtime segment into the focused window,
+// after removing text that voice commands in it retracted from earlier segments.
+// Segments are dropped when focus moved away from the window recording began in.
+func (s *Server) typeLiveSegment(segment audio.Segment) {
+	for _, retraction := range segment.Retractions {
+		s.retractLiveText(retraction)
+	}
+	if strings.TrimSpace(segment.Text) == "" {
+		return
+	}
+
+	entry := liveSegment{text: segment.Text}
+	defer func() { s.liveSegments = append(s.liveSegments, entry) }()
+
+	if current := history.CurrentTarget(); current != s.liveTypingTarget {
+		logger.Debugf("D-Bus: Live typing skipped - focus changed from %q (%s) to %q (%s)",
+			s.liveTypingTarget.Title, s.liveTypingTarget.App, current.Title, current.App)
+		return
+	}
+
+	s.typeLive(&entry)
+}
+
+// typeLive types the segment after the text typed before it, rewritten with
+// the profile of the window
+func (s *Server) typeLive(entry *liveSegment) {
+	result := rewrite.Apply(strings.TrimSpace(entry.text))
+	text := joinLiveSegment(s.liveTypedText(), result.Text)
+	if err := s.EmitRequestPaste(text); err != nil {
+		logger.Errorf("D-Bus: Live typing failed", err)
+		return
+	}
+	entry.typed = text
+	entry.rewritten = result.Rewritten
+	if history.ReturnAfterPaste() {
+		// The extension pressed Return after the paste
+		entry.typed += "\n"
+	}
+	history.Record(text)
+}
+
+// retractLiveText removes text a voice command retracted from the last
+// segment, deleting it from the window when the segment was typed there
+func (s *Server) retractLiveText(retraction audio.Retraction) {
+	if len(s.liveSegments) == 0 {
+		return
+	}
+	last := &s.liveSegments[len(s.liveSegments)-1]
+
+	// Trailing line breaks are never typed, so they are not deleted either
+	chars := utf8.RuneCountInString(strings.TrimRightFunc(retraction.Text, unicode.IsSpace))
+	if chars > 0 && strings.HasSuffix(last.typed, "\n") {
+		// Backspace removes the Return pressed after the paste first
+		chars++
+	}
+	typed := []rune(last.typed)
+	// A rewritten segment does not end with the retracted words as said, so
+	// all of it is deleted and the rest typed again
+	retype := last.rewritten && chars > 0 && !retraction.Whole
+	if retraction.Whole || retype || chars > len(typed) {
+		chars = len(typed)
+	}
+	last.typed = string(typed[:len(typed)-chars])
+	last.text = strings.TrimSuffix(last.text, retraction.Text)
+	if retraction.Whole {
+		s.liveSegments = s.liveSegments[:len(s.liveSegments)-1]
+	}
+	if chars == 0 {
+		return
+	}
+
+	if current := history.CurrentTarget(); current != s.liveTypingTarget {
+		logger.Warnf("D-Bus: Cannot delete %d live typed characters - focus changed to %q (%s)",
+			chars, current.Title, current.App)
+		return
+	}
+	if err := s.EmitRequestDelete(chars); err != nil {
+		logger.Errorf("D-Bus: Deleting live typed text failed", err)
+		return
+	}
+	history.Shorten(chars)
+	if retype && strings.TrimSpace(last.text) != "" {
+		s.typeLive(last)
+	}
+}
+
+// recordLivePastes reports the segments typed during the session, after
+// retractions, as the session's pastes
+func (s *Server) recordLivePastes() {
+	s.resetRewrites()
+	for _, seg := range s.liveSegments {
+		if seg.typed != "" {
+			s.RecordPaste(rewrite.Result{Raw: seg.text, Text: seg.typed, Rewritten: seg.rewritten})
+		}
+	}
+}
+
+// reportSkippedLiveSegments copies the segments that were not typed because
+// focus moved to the clipboard and tells the user about them
+func (s *Server) reportSkippedLiveSegments() {
+	var skipped []string
+	for _, seg := range s.liveSegments {
+		if seg.typed == "" && strings.TrimSpace(seg.text) != "" {
+			skipped = append(skipped, strings.TrimSpace(seg.text))
+		}
+	}
+	if len(skipped) == 0 {
+		return
+	}
+
+	text := strings.Join(skipped, " ")
+	logger.Infof("D-Bus: %d live segments were not typed because focus changed", len(skipped))
+	if err := clipboard.CopyToClipboard(text); err != nil {
+		logger.Errorf("D-Bus: Failed to copy skipped live segments", err)
+	}
+	if err := s.EmitLiveTypingSkipped(text); err != nil {
+		logger.Warnf("D-Bus: Failed to report skipped live segments: %v", err)
+	}
+}
+
+// liveTypedText returns the text typed into the window during this session
+func (s *Server) liveTypedText() string {
+	var b strings.Builder
+	for _, seg := range s.liveSegments {
+		b.WriteString(seg.typed)
+	}
+	return b.String()
+}
+
+// joinLiveSegment prepares a segment for insertion after already typed text:
+// it adds a separating space and fixes the capitalization of the first word
+// depending on whether the previous segment ended a sentence.
+func joinLiveSegment(typed, segment string) string {
+	segment = strings.TrimSpace(segment)
+	if segment == "" {
+		return ""
+	}
+
+	previous := strings.TrimRightFunc(typed, unicode.IsSpace)
+	if previous == "" {
+		return segment
+	}
+
+	lastRune, _ := utf8.DecodeLastRuneInString(previous)
+	if strings.ContainsRune(".!?…", lastRune) {
+		segment = upperFirst(segment)
+	} else if shouldLowerFirst(segment) {
+		segment = lowerFirst(segment)
+	}
+
+	firstRune, _ := utf8.DecodeRuneInString(segment)
+	if strings.ContainsRune(".,;:!?)…", firstRune) || len(previous) < len(typed) {
+		// Punctuation attaches to the previous word; typed already ends with a space
+		return segment
+	}
+	return " " + segment
+}
+
+// shouldLowerFirst reports whether the first word looks like an ordinary word
+// capitalized only because the model started a new segment
+func shouldLowerFirst(segment string) bool {
+	word := strings.FieldsFunc(segment, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+	if len(word) == 0 {
+		return false
+	}
+	runes := []rune(word[0])
+	if len(runes) < 2 || !unicode.IsUpper(runes[0]) {
+		// Single letters ("I") and lowercase words stay as they are
+		return false
+	}
+	for _, r := range runes[1:] {
+		if unicode.IsUpper(r) {
+			// Acronyms and camel case names stay as they are
+			return false
+		}
+	}
+	return true
+}
+
+// upperFirst uppercases the first letter of text
+func upperFirst(text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	return string(unicode.ToUpper(r)) + text[size:]
+}
+
+// lowerFirst lowercases the first letter of text
+func lowerFirst(text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	return string(unicode.ToLower(r)) + text[size:]
+}
@@ -2,6 +2,7 @@ package dbus
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -10,11 +11,18 @@ import (
 
 	"github.com/dooshek/voicify/internal/audio"
 	"github.com/dooshek/voicify/internal/clipboard"
+	"github.com/dooshek/voicify/internal/history"
 	"github.com/dooshek/voicify/internal/logger"
 	"github.com/dooshek/voicify/internal/notification"
+	"github.com/dooshek/voicify/internal/plugin"
+	"github.com/dooshek/voicify/internal/postprocess"
+	"github.com/dooshek/voicify/internal/rewrite"
+	"github.com/dooshek/voicify/internal/spelling"
 	"github.com/dooshek/voicify/internal/state"
 	"github.com/dooshek/voicify/internal/stats"
 	"github.com/dooshek/voicify/internal/transcriptionrouter"
+	"github.com/dooshek/voicify/internal/types"
+	"github.com/dooshek/voicify/internal/voicecommand"
 	"github.com/godbus/dbus/v5"
 	"github.com/godbus/dbus/v5/introspect"
 )
@@ -23,6 +31,13 @@ const (
 	dbusServiceName = "com.dooshek.voicify"
 	dbusObjectPath  = "/com/dooshek/voicify/Recorder"
 	dbusInterface   = "com.dooshek.voicify.Recorder"
+
+	// partialEmitInterval throttles PartialTranscription signals so frontends
+	// are not flooded with one signal per streamed delta
+	partialEmitInterval = 150 * time.Millisecond
+
+	// hybridStatsPrefix keeps hybrid recordings separate from plain model stats
+	hybridStatsPrefix = "hybrid/"
 )
 
 // Server implements D-Bus service for voicify recording
@@ -30,7 +45,9 @@ type Server struct {
 	conn                        *dbus.Conn
 	recorder                    *audio.Recorder
 	realtimeRecorder            *audio.RealtimeRecorder
+	hybridRecorder              *audio.HybridRecorder
 	isRealtimeMode              bool
+	isHybridMode                bool // Realtime preview with batch final pass
 	postTranscriptionRouterMode bool // Post-transcription mode with router
 	postTranscriptionAutoPaste  bool // Post-transcription mode with auto-paste
 	ctx                         context.Context
@@ -38,10 +55,16 @@ type Server struct {
 	mu                          sync.Mutex
 	// level forwarding
 	levelForwardCancel context.CancelFunc
-	// realtime transcription forwarding
+	// realtime transcription forwarding; done is closed when the forwarder exits
 	realtimeForwardCancel context.CancelFunc
-	// accumulated realtime transcription across complete chunks
-	realtimeAccum string
+	realtimeForwardDone   chan struct{}
+	// live typing of completed realtime segments into the focused window
+	liveTyping       bool
+	liveTypingTarget history.Target
+	liveSegments     []liveSegment
+	// texts prepared for pasting during the recording, reported with TranscriptionReady
+	rewriteMu sync.Mutex
+	rewrites  []rewrite.Result
 	// media playback state tracking
 	wasMediaPlaying   bool
 	autoPausePlayback bool
@@ -70,6 +93,20 @@ func NewServer() (*Server, error) {
 		return nil, fmt.Errorf("failed to initialize realtime recorder: %w", err)
 	}
 
+	// Voice commands spoken during realtime dictation (graceful degradation if fails)
+	grammar, err := voicecommand.LoadGrammar()
+	if err != nil {
+		logger.Error("Failed to load voice command grammar, voice commands will be unavailable", err)
+	} else {
+		realtimeRecorder.SetCommandGrammar(grammar)
+	}
+
+	// Initialize hybrid recorder on top of the realtime one
+	hybridRecorder, err := audio.NewHybridRecorder(realtimeRecorder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize hybrid recorder: %w", err)
+	}
+
 	// Initialize stats manager (graceful degradation if fails)
 	statsManager, err := stats.NewStatsManager()
 	if err != nil {
@@ -82,11 +119,14 @@ func NewServer() (*Server, error) {
 	// Default transcription models - extension overrides via SetTranscriptionModels D-Bus call
 	const defaultModel = "gpt-4o-mini-transcribe"
 	// Set in state so standard transcriber picks it up immediately
-	state.Get().Config.LLM.Transcription.Model = defaultModel
+	state.Get().UpdateConfig(func(cfg *types.Config) {
+		cfg.LLM.Transcription.Model = defaultModel
+	})
 
 	return &Server{
 		recorder:           recorder,
 		realtimeRecorder:   realtimeRecorder,
+		hybridRecorder:     hybridRecorder,
 		statsManager:       statsManager,
 		transcriptionModel: defaultModel,
 		realtimeModel:      defaultModel,
@@ -97,6 +137,13 @@ func NewServer() (*Server, error) {
 }
 
 // Start starts the D-Bus server
+//
+// Realtime signal contract: while the user speaks, the daemon emits
+// PartialTranscription with the full text of the current segment so far
+// (deltas are assembled by the daemon, at most once per partialEmitInterval).
+// When the segment is finalized, CompleteTranscription carries its final
+// text and the next PartialTranscription starts a new segment from scratch.
+// Frontends should replace, not append, the live text on each partial signal.
 func (s *Server) Start() error {
 	var err error
 	s.conn, err = dbus.ConnectSessionBus()
@@ -131,12 +178,18 @@ func (s *Server) Start() error {
 				{
 					Name: "StartRealtimeRecording",
 				},
+				{
+					Name: "ToggleHybridRecording",
+				},
 				{
 					Name: "GetStatus",
 					Args: []introspect.Arg{
 						{Name: "is_recording", Type: "b", Direction: "out"},
 					},
 				},
+				{
+					Name: "StopRealtimeRecording",
+				},
 				{
 					Name: "CancelRecording",
 				},
@@ -159,6 +212,36 @@ func (s *Server) Start() error {
 						{Name: "enabled", Type: "b", Direction: "in"},
 					},
 				},
+				{
+					Name: "SetLiveTyping",
+					Args: []introspect.Arg{
+						{Name: "enabled", Type: "b", Direction: "in"},
+					},
+				},
+				{
+					Name: "SetEnterAfterPaste",
+					Args: []introspect.Arg{
+						{Name: "enabled", Type: "b", Direction: "in"},
+					},
+				},
+				{
+					Name: "ToggleSpellingMode",
+					Args: []introspect.Arg{
+						{Name: "enabled", Type: "b", Direction: "out"},
+					},
+				},
+				{
+					Name: "ChooseRoutingCandidate",
+					Args: []introspect.Arg{
+						{Name: "action", Type: "s", Direction: "in"},
+					},
+				},
+				{
+					Name: "UndoLast",
+				},
+				{
+					Name: "RedoLast",
+				},
 				{
 					Name: "GetRecordingStats",
 					Args: []introspect.Arg{
@@ -175,6 +258,9 @@ func (s *Server) Start() error {
 						{Name: "realtime", Type: "s", Direction: "in"},
 					},
 				},
+				{
+					Name: "Reload",
+				},
 			},
 			Signals: []introspect.Signal{
 				{Name: "RecordingStarted"},
@@ -182,6 +268,7 @@ func (s *Server) Start() error {
 					Name: "TranscriptionReady",
 					Args: []introspect.Arg{
 						{Name: "text", Type: "s"},
+						{Name: "rewritten", Type: "s"},
 					},
 				},
 				{
@@ -202,6 +289,7 @@ func (s *Server) Start() error {
 						{Name: "error", Type: "s"},
 					},
 				},
+				{Name: "RecordingStopped"},
 				{Name: "RecordingCancelled"},
 				{
 					Name: "InputLevel",
@@ -215,6 +303,41 @@ func (s *Server) Start() error {
 						{Name: "text", Type: "s"},
 					},
 				},
+				{
+					Name: "RequestDelete",
+					Args: []introspect.Arg{
+						{Name: "chars", Type: "u"},
+					},
+				},
+				{
+					Name: "RoutingAmbiguous",
+					Args: []introspect.Arg{
+						{Name: "transcription", Type: "s"},
+						{Name: "candidates", Type: "a(sd)"},
+					},
+				},
+				{
+					Name: "ActionStepCompleted",
+					Args: []introspect.Arg{
+						{Name: "index", Type: "u"},
+						{Name: "total", Type: "u"},
+						{Name: "action", Type: "s"},
+						{Name: "success", Type: "b"},
+						{Name: "output", Type: "s"},
+					},
+				},
+				{
+					Name: "ActionCompleted",
+					Args: []introspect.Arg{
+						{Name: "report", Type: "s"},
+					},
+				},
+				{
+					Name: "LiveTypingSkipped",
+					Args: []introspect.Arg{
+						{Name: "text", Type: "s"},
+					},
+				},
 			},
 		}},
 	}
@@ -265,8 +388,11 @@ func (s *Server) TogglePostTranscriptionAutoPaste() *dbus.Error {
 		s.postTranscriptionAutoPaste = true
 		s.postTranscriptionRouterMode = false
 		s.isRealtimeMode = false
+		s.isHybridMode = false
+		state.Get().SetRecordingMode(types.RecordingModeAutoPaste)
 
 		s.recordingStartTime = time.Now()
+		s.resetRewrites()
 		s.recorder.Start()
 		s.emitSignal("RecordingStarted")
 		s.startForwardingLevels()
@@ -295,8 +421,11 @@ func (s *Server) TogglePostTranscriptionRouter() *dbus.Error {
 		s.postTranscriptionRouterMode = true
 		s.postTranscriptionAutoPaste = false
 		s.isRealtimeMode = false
+		s.isHybridMode = false
+		state.Get().SetRecordingMode(types.RecordingModeRouter)
 
 		s.recordingStartTime = time.Now()
+		s.resetRewrites()
 		s.recorder.Start()
 		s.emitSignal("RecordingStarted")
 		s.startForwardingLevels()
@@ -320,10 +449,15 @@ func (s *Server) StartRealtimeRecording() *dbus.Error {
 	s.wasMediaPlaying = s.pauseAndCheckMediaPlaying()
 
 	s.isRealtimeMode = true
-	// reset accumulator for this session
-	s.realtimeAccum = ""
+	s.isHybridMode = false
+	state.Get().SetRecordingMode(types.RecordingModeRealtime)
+
+	// Live typing only targets the window the recording started in
+	s.liveTypingTarget = history.CurrentTarget()
+	s.liveSegments = nil
 
 	s.recordingStartTime = time.Now()
+	s.resetRewrites()
 
 	// Set realtime model if overridden
 	if s.realtimeModel != "" {
@@ -349,6 +483,55 @@ func (s *Server) StartRealtimeRecording() *dbus.Error {
 	return nil
 }
 
+// ToggleHybridRecording toggles hybrid recording: realtime preview while speaking,
+// batch transcription with the standard model on stop (D-Bus method)
+func (s *Server) ToggleHybridRecording() *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logger.Debugf("D-Bus: ToggleHybridRecording called")
+
+	if s.recorder.IsRecording() || s.realtimeRecorder.IsRecording() {
+		if !s.isHybridMode {
+			return dbus.MakeFailedError(fmt.Errorf("recording already in progress"))
+		}
+		// Already recording - stop it
+		s.stopHybridLocked()
+		return nil
+	}
+
+	// Check if media is currently playing before starting recording
+	s.wasMediaPlaying = s.pauseAndCheckMediaPlaying()
+
+	s.isHybridMode = true
+	s.isRealtimeMode = false
+	s.postTranscriptionAutoPaste = false
+	s.postTranscriptionRouterMode = false
+	state.Get().SetRecordingMode(types.RecordingModeHybrid)
+
+	s.recordingStartTime = time.Now()
+	s.resetRewrites()
+
+	if s.realtimeModel != "" {
+		s.realtimeRecorder.SetRealtimeModel(s.realtimeModel)
+	}
+
+	logger.Debugf("D-Bus: Starting hybrid recording")
+	if err := s.hybridRecorder.Start(); err != nil {
+		s.isHybridMode = false
+		logger.Errorf("D-Bus: Failed to start hybrid recording", err)
+		return dbus.MakeFailedError(fmt.Errorf("failed to start hybrid recording: %w", err))
+	}
+
+	s.emitSignal("RecordingStarted")
+
+	// Live preview comes from the realtime stream
+	s.startForwardingRealtimeTranscription()
+	s.startForwardingRealtimeLevels()
+
+	return nil
+}
+
 // GetStatus returns current recording status (D-Bus method)
 func (s *Server) GetStatus() (bool, *dbus.Error) {
 	return s.recorder.IsRecording() || s.realtimeRecorder.IsRecording(), nil
@@ -361,41 +544,38 @@ func (s *Server) CancelRecording() *dbus.Error {
 
 	logger.Debugf("D-Bus: CancelRecording called")
 
-	if s.isRealtimeMode {
+	// Also abort actions still running for an earlier recording
+	if cancelled := transcriptionrouter.CancelRouting(); cancelled > 0 {
+		logger.Infof("D-Bus: Cancelled %d routing requests in progress", cancelled)
+	}
+
+	if s.isHybridMode {
 		if !s.realtimeRecorder.IsRecording() {
-			logger.Debugf("D-Bus: No realtime recording in progress, cancel is no-op")
+			logger.Debugf("D-Bus: No hybrid recording in progress, cancel is no-op")
 			return nil
 		}
 
-		logger.Debugf("D-Bus: Cancelling realtime recording")
-		s.realtimeRecorder.Cancel()
+		logger.Debugf("D-Bus: Cancelling hybrid recording")
+		s.hybridRecorder.Cancel()
 		s.stopForwardingRealtimeTranscription()
 		s.stopForwardingRealtimeLevels()
+		s.isHybridMode = false
 
 		// Resume media playback after recording stops
 		go s.resumeMediaPlayback()
-
-		// After cancelling realtime, route the accumulated transcription if present
-		finalText := s.realtimeAccum
-		s.realtimeAccum = "" // reset for next run
-
-		// Track stats for realtime recording
-		if s.statsManager != nil && finalText != "" {
-			duration := time.Since(s.recordingStartTime).Seconds()
-			s.statsManager.AddRecording(s.realtimeModel, duration)
+	} else if s.isRealtimeMode {
+		if !s.realtimeRecorder.IsRecording() {
+			logger.Debugf("D-Bus: No realtime recording in progress, cancel is no-op")
+			return nil
 		}
 
-		if finalText != "" {
-			// Route via router
-			router := transcriptionrouter.New(finalText)
-			if err := router.Route(finalText); err != nil {
-				logger.Errorf("D-Bus: Error routing realtime transcription", err)
-				s.emitSignal("RecordingError", fmt.Sprintf("routing error: %v", err))
-			} else {
-				// Emit final transcription ready for any listeners
-				s.emitSignal("TranscriptionReady", finalText)
-			}
-		}
+		logger.Debugf("D-Bus: Cancelling realtime recording")
+		s.stopForwardingRealtimeTranscription()
+		s.stopForwardingRealtimeLevels()
+		s.realtimeRecorder.Cancel()
+
+		// Resume media playback after recording stops
+		go s.resumeMediaPlayback()
 	} else {
 		if !s.recorder.IsRecording() {
 			logger.Debugf("D-Bus: No recording in progress, cancel is no-op")
@@ -416,6 +596,26 @@ func (s *Server) CancelRecording() *dbus.Error {
 	return nil
 }
 
+// StopRealtimeRecording stops realtime recording and routes the assembled
+// transcription (D-Bus method)
+func (s *Server) StopRealtimeRecording() *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logger.Debugf("D-Bus: StopRealtimeRecording called")
+
+	if !s.isRealtimeMode || !s.realtimeRecorder.IsRecording() {
+		logger.Debugf("D-Bus: No realtime recording in progress, stop is no-op")
+		return nil
+	}
+
+	s.stopForwardingRealtimeLevels()
+	s.stopRealtimeAsync(s.detachRealtimeForwarding())
+
+	s.emitSignal("RecordingStopped")
+	return nil
+}
+
 // UpdateFocusedWindow updates the cached focused window info (D-Bus method)
 func (s *Server) UpdateFocusedWindow(title string, app string) *dbus.Error {
 	logger.Debugf("D-Bus: UpdateFocusedWindow called - title: %s, app: %s", title, app)
@@ -434,6 +634,61 @@ func (s *Server) SetAutoPausePlayback(enabled bool) *dbus.Error {
 	return nil
 }
 
+// SetLiveTyping enables or disables typing completed realtime segments into the focused window
+func (s *Server) SetLiveTyping(enabled bool) *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.liveTyping = enabled
+	logger.Debugf("D-Bus: SetLiveTyping = %v", enabled)
+	return nil
+}
+
+// SetEnterAfterPaste tells the daemon whether the extension presses Return
+// after each paste, so undo deletes it as well
+func (s *Server) SetEnterAfterPaste(enabled bool) *dbus.Error {
+	history.SetReturnAfterPaste(enabled)
+	logger.Debugf("D-Bus: SetEnterAfterPaste = %v", enabled)
+	return nil
+}
+
+// ToggleSpellingMode switches spelling mode, in which every utterance is pasted
+// as a spelled character string, and returns the new state (D-Bus method)
+func (s *Server) ToggleSpellingMode() (bool, *dbus.Error) {
+	enabled := !state.Get().IsSpellingMode()
+	state.Get().SetSpellingMode(enabled)
+	logger.Infof("D-Bus: Spelling mode = %v", enabled)
+	return enabled, nil
+}
+
+// ChooseRoutingCandidate runs the action the user picked after a
+// RoutingAmbiguous signal; "default" pastes the transcription (D-Bus method)
+func (s *Server) ChooseRoutingCandidate(action string) *dbus.Error {
+	logger.Debugf("D-Bus: ChooseRoutingCandidate called - action: %s", action)
+	if err := transcriptionrouter.GetOrCreateGlobalRouter().ChooseCandidate(action); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// UndoLast removes the last text voicify inserted, provided focus has not
+// moved to another window since (D-Bus method)
+func (s *Server) UndoLast() *dbus.Error {
+	logger.Debugf("D-Bus: UndoLast called")
+	if err := plugin.UndoLast(); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// RedoLast inserts the last undone text again (D-Bus method)
+func (s *Server) RedoLast() *dbus.Error {
+	logger.Debugf("D-Bus: RedoLast called")
+	if err := plugin.RedoLast(); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
 // GetRecordingStats returns recording statistics as JSON (D-Bus method)
 func (s *Server) GetRecordingStats() (string, *dbus.Error) {
 	if s.statsManager == nil {
@@ -464,7 +719,9 @@ func (s *Server) SetTranscriptionModels(standard string, realtime string) *dbus.
 	if standard != "" {
 		s.transcriptionModel = standard
 		// Update state so openai/groq transcriber picks it up
-		state.Get().Config.LLM.Transcription.Model = standard
+		state.Get().UpdateConfig(func(cfg *types.Config) {
+			cfg.LLM.Transcription.Model = standard
+		})
 		logger.Debugf("D-Bus: Transcription model set to: %s", standard)
 	}
 	if realtime != "" {
@@ -474,6 +731,25 @@ func (s *Server) SetTranscriptionModels(standard string, realtime string) *dbus.
 	return nil
 }
 
+// OverrideConfig keeps the models set by the extension when the config
+// file is reloaded
+func (s *Server) OverrideConfig(cfg *types.Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg.LLM.Transcription.Model = s.transcriptionModel
+}
+
+// Reload re-reads the config file and rebuilds the router, its plugins and
+// prompt. A recording in progress is not interrupted. (D-Bus method)
+func (s *Server) Reload() *dbus.Error {
+	logger.Info("D-Bus: Reloading configuration")
+	if err := transcriptionrouter.Reload(); err != nil {
+		logger.Error("Reload failed", err)
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
 // pauseAndCheckMediaPlaying pauses media if playing and returns whether it was playing.
 // Must be called with s.mu held.
 func (s *Server) pauseAndCheckMediaPlaying() bool {
@@ -563,6 +839,15 @@ func (s *Server) stopPostTranscriptionAutoPasteAsync() {
 		go s.resumeMediaPlayback()
 
 		logger.Debugf("D-Bus: Post-transcription auto-paste received: %s", transcription)
+		transcription = s.processTranscription(transcription)
+		pasted := transcription
+		if spelled, handled := spelling.Interpret(transcription); handled {
+			// No router in this mode - paste the spelled text directly
+			transcription = spelled
+			pasted = spelled
+		} else {
+			pasted = rewrite.ForPaste(transcription)
+		}
 
 		// Track recording stats
 		if s.statsManager != nil {
@@ -571,7 +856,7 @@ func (s *Server) stopPostTranscriptionAutoPasteAsync() {
 		}
 
 		// Copy to clipboard and trigger paste via extension
-		if err := clipboard.CopyToClipboard(transcription); err != nil {
+		if err := clipboard.CopyToClipboard(pasted); err != nil {
 			logger.Error("D-Bus: Failed to copy to clipboard", err)
 			s.emitSignal("RecordingError", "clipboard error")
 			s.postTranscriptionAutoPaste = false
@@ -582,7 +867,8 @@ func (s *Server) stopPostTranscriptionAutoPasteAsync() {
 		s.postTranscriptionAutoPaste = false
 
 		// Emit signal that transcription is ready for auto-paste
-		s.emitSignal("TranscriptionReady", transcription)
+		s.emitTranscriptionReady(transcription)
+		history.Record(pasted)
 	}()
 }
 
@@ -607,6 +893,7 @@ func (s *Server) stopPostTranscriptionRouterAsync() {
 		go s.resumeMediaPlayback()
 
 		logger.Debugf("D-Bus: Post-transcription router received: %s", transcription)
+		transcription = s.processTranscription(transcription)
 
 		// Track recording stats
 		if s.statsManager != nil {
@@ -625,7 +912,107 @@ func (s *Server) stopPostTranscriptionRouterAsync() {
 		s.postTranscriptionRouterMode = false
 
 		// Emit signal that transcription is ready (but not auto-pasted)
-		s.emitSignal("TranscriptionReady", transcription)
+		s.emitTranscriptionReady(transcription)
+	}()
+}
+
+// stopRealtimeAsync stops realtime recording and routes the transcript
+// assembled from all realtime segments. stopForwarding ends the session's
+// forwarder once the segments the recorder drained were forwarded.
+func (s *Server) stopRealtimeAsync(stopForwarding func()) {
+	go func() {
+		logger.Debugf("D-Bus: Stopping realtime recording")
+
+		finalText, err := s.realtimeRecorder.Stop()
+		stopForwarding()
+		if err != nil {
+			logger.Errorf("D-Bus: Error stopping realtime recording", err)
+		}
+
+		// Resume media playback after recording stops
+		go s.resumeMediaPlayback()
+
+		if s.liveTyping {
+			s.reportSkippedLiveSegments()
+			s.recordLivePastes()
+		}
+		finalText = s.processTranscription(finalText)
+
+		// Track stats for realtime recording
+		if s.statsManager != nil && finalText != "" {
+			duration := time.Since(s.recordingStartTime).Seconds()
+			s.statsManager.AddRecording(s.realtimeModel, duration)
+		}
+
+		if finalText == "" {
+			return
+		}
+		router := transcriptionrouter.New(finalText)
+		// Without live typing nothing was inserted while talking, so the
+		// transcript is pasted unless an action handles it
+		route := router.RouteDictation
+		if s.liveTyping {
+			// Segments were already typed live - run the actions without pasting them again
+			route = router.RouteTyped
+		}
+		if err := route(finalText); err != nil {
+			logger.Errorf("D-Bus: Error routing realtime transcription", err)
+			s.emitSignal("RecordingError", fmt.Sprintf("routing error: %v", err))
+			return
+		}
+		// Emit final transcription ready for any listeners
+		s.emitTranscriptionReady(finalText)
+	}()
+}
+
+// stopHybridAsync stops hybrid recording, runs the batch pass and routes the
+// result. stopForwarding ends the session's preview forwarder.
+func (s *Server) stopHybridAsync(stopForwarding func()) {
+	go func() {
+		logger.Debugf("D-Bus: Stopping hybrid recording")
+
+		s.stopForwardingRealtimeLevels()
+
+		result, err := s.hybridRecorder.Stop()
+		stopForwarding()
+
+		// Resume media playback after recording stops
+		go s.resumeMediaPlayback()
+
+		if err != nil {
+			logger.Errorf("D-Bus: Error stopping hybrid recording", err)
+			s.emitSignal("RecordingError", err.Error())
+			s.isHybridMode = false
+			return
+		}
+
+		if result.UsedFallback {
+			logger.Warnf("D-Bus: Hybrid batch pass failed, routing realtime preview")
+		}
+		logger.Debugf("D-Bus: Hybrid transcription received: %s", result.Text)
+		result.Text = s.processTranscription(result.Text)
+
+		// Track recording stats separately from plain realtime and batch
+		// recordings, under both models that transcribed it
+		if s.statsManager != nil {
+			duration := time.Since(s.recordingStartTime).Seconds()
+			s.statsManager.AddRecording(hybridStatsPrefix+s.realtimeModel+"+"+s.transcriptionModel, duration)
+		}
+
+		// Route through router - plugins may call RequestPaste. Nothing is
+		// left when a voice command cancelled everything.
+		if result.Text != "" {
+			router := transcriptionrouter.New(result.Text)
+			if err := router.Route(result.Text); err != nil {
+				logger.Errorf("D-Bus: Error routing hybrid transcription", err)
+				s.emitSignal("RecordingError", fmt.Sprintf("routing error: %v", err))
+			}
+		}
+
+		// Reset mode
+		s.isHybridMode = false
+
+		s.emitTranscriptionReady(result.Text)
 	}()
 }
 
@@ -651,6 +1038,64 @@ func (s *Server) emitSignal(name string, args ...interface{}) {
 	}
 }
 
+// processTranscription cleans up a transcription. Spelled input is passed
+// through untouched so the spelling plugin sees the spoken letters. The LLM
+// rewrite is left to the paste, so routing sees what was said.
+func (s *Server) processTranscription(text string) string {
+	if spelling.Requested(text) {
+		return text
+	}
+	return postprocess.Apply(text)
+}
+
+// RecordPaste remembers a text prepared for pasting, so TranscriptionReady
+// reports the rewritten text next to the raw one
+func (s *Server) RecordPaste(result rewrite.Result) {
+	s.rewriteMu.Lock()
+	s.rewrites = append(s.rewrites, result)
+	s.rewriteMu.Unlock()
+}
+
+// resetRewrites forgets the pastes of the previous recording
+func (s *Server) resetRewrites() {
+	s.rewriteMu.Lock()
+	s.rewrites = nil
+	s.rewriteMu.Unlock()
+}
+
+// emitTranscriptionReady emits a TranscriptionReady signal with the raw
+// transcription and the text pasted for it when a rewrite profile changed
+// it, empty otherwise. Live typed segments are pasted one by one, so their
+// texts are joined.
+func (s *Server) emitTranscriptionReady(text string) {
+	s.rewriteMu.Lock()
+	results := s.rewrites
+	s.rewrites = nil
+	s.rewriteMu.Unlock()
+
+	rewritten := false
+	pasted := make([]string, 0, len(results))
+	for _, result := range results {
+		rewritten = rewritten || result.Rewritten
+		pasted = append(pasted, strings.TrimSpace(result.Text))
+	}
+	if !rewritten {
+		pasted = nil
+	}
+	s.emitSignal("TranscriptionReady", text, strings.Join(pasted, " "))
+}
+
+// EmitLiveTypingSkipped emits a LiveTypingSkipped signal with the segments
+// that were not typed because focus moved to another window
+func (s *Server) EmitLiveTypingSkipped(text string) error {
+	if s.conn == nil {
+		return fmt.Errorf("no D-Bus connection")
+	}
+
+	s.emitSignal("LiveTypingSkipped", text)
+	return nil
+}
+
 // EmitRequestPaste emits a RequestPaste signal for plugins to trigger text insertion
 func (s *Server) EmitRequestPaste(text string) error {
 	if s.conn == nil {
@@ -662,6 +1107,56 @@ func (s *Server) EmitRequestPaste(text string) error {
 	return nil
 }
 
+// EmitRoutingAmbiguous emits a RoutingAmbiguous signal listing the actions
+// the router could not decide between, most likely first
+func (s *Server) EmitRoutingAmbiguous(transcription string, candidates []transcriptionrouter.Candidate) error {
+	if s.conn == nil {
+		return fmt.Errorf("no D-Bus connection")
+	}
+
+	s.emitSignal("RoutingAmbiguous", transcription, candidates)
+	return nil
+}
+
+// EmitActionStepCompleted emits an ActionStepCompleted signal after each step
+// of a multi-action plan; output is the error message when the step failed
+func (s *Server) EmitActionStepCompleted(result transcriptionrouter.StepResult) error {
+	if s.conn == nil {
+		return fmt.Errorf("no D-Bus connection")
+	}
+
+	s.emitSignal("ActionStepCompleted", uint32(result.Index), uint32(result.Total),
+		result.Action, result.Success, result.Output)
+	return nil
+}
+
+// EmitActionCompleted emits an ActionCompleted signal with the JSON report of
+// the actions a routing request ran
+func (s *Server) EmitActionCompleted(report transcriptionrouter.Report) error {
+	if s.conn == nil {
+		return fmt.Errorf("no D-Bus connection")
+	}
+
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode action report: %w", err)
+	}
+	s.emitSignal("ActionCompleted", string(payload))
+	return nil
+}
+
+// EmitRequestDelete emits a RequestDelete signal asking the extension to press
+// Backspace chars times in the focused window
+func (s *Server) EmitRequestDelete(chars int) error {
+	if s.conn == nil {
+		return fmt.Errorf("no D-Bus connection")
+	}
+
+	logger.Debugf("D-Bus: Requesting deletion of %d characters", chars)
+	s.emitSignal("RequestDelete", uint32(chars))
+	return nil
+}
+
 // startForwardingLevels begins reading from recorder.LevelChan() and emits InputLevel signals
 func (s *Server) startForwardingLevels() {
 	if s.levelForwardCancel != nil {
@@ -700,23 +1195,44 @@ func (s *Server) startForwardingRealtimeTranscription() {
 		return
 	}
 	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
 	s.realtimeForwardCancel = cancel
+	s.realtimeForwardDone = done
 
 	go func() {
+		defer close(done)
+		ticker := time.NewTicker(partialEmitInterval)
+		defer ticker.Stop()
+
+		// currentSegment is the latest text of the segment being spoken;
+		// partialDirty is set when it changed since the last emit
+		currentSegment := ""
+		partialDirty := false
+
 		for {
 			select {
 			case <-ctx.Done():
 				return
+			case segment := <-s.realtimeRecorder.PartialChan():
+				currentSegment = segment
+				partialDirty = true
+			case <-ticker.C:
+				if partialDirty {
+					s.emitSignal("PartialTranscription", currentSegment)
+					partialDirty = false
+				}
 			case complete := <-s.realtimeRecorder.CompleteChan():
-				logger.Debugf("D-Bus: Emitting complete transcription: %s", complete)
-				s.emitSignal("CompleteTranscription", complete)
-				// Accumulate complete chunks for final routing on cancel
-				if s.realtimeAccum == "" {
-					s.realtimeAccum = complete
-				} else {
-					// Add a space/newline between segments
-					s.realtimeAccum = s.realtimeAccum + " " + complete
+				logger.Debugf("D-Bus: Emitting complete transcription: %s", complete.Text)
+				s.emitSignal("CompleteTranscription", complete.Text)
+				if s.liveTyping && s.isRealtimeMode {
+					s.typeLiveSegment(complete)
 				}
+				// Segment is final - next partials start a new one
+				currentSegment = ""
+				partialDirty = false
+			case <-s.realtimeRecorder.StopRequestChan():
+				logger.Debugf("D-Bus: Stop requested by voice command")
+				go s.stopRealtimeByVoice()
 			case err := <-s.realtimeRecorder.ErrorChan():
 				logger.Errorf("D-Bus: Realtime transcription error", err)
 				s.emitSignal("RecordingError", err.Error())
@@ -725,11 +1241,48 @@ func (s *Server) startForwardingRealtimeTranscription() {
 	}()
 }
 
+// stopRealtimeByVoice ends the realtime or hybrid session the same way the
+// extension would, after a spoken stop command
+func (s *Server) stopRealtimeByVoice() {
+	s.mu.Lock()
+	if s.isHybridMode {
+		// Checked under the same lock: a toggle after the session ended would start a new one
+		if s.realtimeRecorder.IsRecording() {
+			s.stopHybridLocked()
+		}
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+
+	// A no-op when the recording already stopped
+	s.StopRealtimeRecording()
+}
+
+// stopHybridLocked stops the hybrid recording in progress. Must be called with s.mu held.
+func (s *Server) stopHybridLocked() {
+	logger.Debugf("D-Bus: Stopping hybrid recording")
+	go s.stopHybridAsync(s.detachRealtimeForwarding())
+}
+
 // stopForwardingRealtimeTranscription stops the realtime transcription forwarding
 func (s *Server) stopForwardingRealtimeTranscription() {
-	if s.realtimeForwardCancel != nil {
-		s.realtimeForwardCancel()
-		s.realtimeForwardCancel = nil
+	s.detachRealtimeForwarding()()
+}
+
+// detachRealtimeForwarding hands the running transcription forwarder to the
+// caller. The returned function stops it and waits until it exited, so a
+// stopping recorder can still deliver its last segments; the next session
+// starts a forwarder of its own.
+func (s *Server) detachRealtimeForwarding() func() {
+	cancel, done := s.realtimeForwardCancel, s.realtimeForwardDone
+	s.realtimeForwardCancel, s.realtimeForwardDone = nil, nil
+	return func() {
+		if cancel == nil {
+			return
+		}
+		cancel()
+		<-done
 	}
 }
 
@@ -0,0 +1,181 @@
+// Package history keeps the text voicify inserted during this session so the
+// last insertion can be undone ("undo that") and redone.
+package history
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dooshek/voicify/internal/state"
+)
+
+// maxInsertions bounds the undo stack
+const maxInsertions = 50
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+	ErrFocusChanged  = errors.New("focus changed since the text was inserted")
+)
+
+// Target identifies the window text was inserted into
+type Target struct {
+	Title string
+	App   string
+}
+
+// Insertion is a piece of text inserted into a window
+type Insertion struct {
+	Text   string
+	Target Target
+	Time   time.Time
+	// Return is set when the extension pressed Return after pasting Text
+	Return bool
+}
+
+var (
+	mu     sync.Mutex
+	done   []Insertion
+	undone []Insertion
+	// returnAfterPaste mirrors the extension's enter-after-paste setting
+	returnAfterPaste bool
+)
+
+// Chars is the number of Backspaces that remove the insertion
+func (i Insertion) Chars() int {
+	chars := utf8.RuneCountInString(i.Text)
+	if i.Return {
+		chars++
+	}
+	return chars
+}
+
+// SetReturnAfterPaste tells whether the extension presses Return after each paste
+func SetReturnAfterPaste(enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	returnAfterPaste = enabled
+}
+
+// ReturnAfterPaste reports whether the extension presses Return after each paste
+func ReturnAfterPaste() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return returnAfterPaste
+}
+
+// CurrentTarget returns the focused window as an insertion target
+func CurrentTarget() Target {
+	title, app := state.Get().GetFocusedWindow()
+	return Target{Title: NormalizeWindowTitle(title), App: app}
+}
+
+// NormalizeWindowTitle strips unsaved-changes markers editors add to titles
+// while the user types, so typing into a window does not look like a focus change
+func NormalizeWindowTitle(title string) string {
+	title = strings.TrimSpace(title)
+	title = strings.TrimPrefix(title, "● ")
+	title = strings.TrimPrefix(title, "*")
+	return strings.TrimSpace(title)
+}
+
+// Record pushes text inserted into the focused window. A new insertion
+// discards the redo stack.
+func Record(text string) {
+	if text == "" {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	done = append(done, Insertion{Text: text, Target: CurrentTarget(), Time: time.Now(), Return: returnAfterPaste})
+	if len(done) > maxInsertions {
+		done = done[len(done)-maxInsertions:]
+	}
+	undone = nil
+}
+
+// PeekUndo returns the insertion Undo would pop, so the caller can remove it
+// from the window first. It fails when focus moved to another window.
+func PeekUndo() (Insertion, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return last(done, ErrNothingToUndo)
+}
+
+// Undo pops the last insertion after the caller removed it. The insertion
+// stays on the stack when focus moved to another window.
+func Undo() (Insertion, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	insertion, err := last(done, ErrNothingToUndo)
+	if err != nil {
+		return Insertion{}, err
+	}
+	done = done[:len(done)-1]
+	undone = append(undone, insertion)
+	return insertion, nil
+}
+
+// PeekRedo returns the insertion Redo would pop, so the caller can insert it
+// first. It fails when focus moved to another window.
+func PeekRedo() (Insertion, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return last(undone, ErrNothingToRedo)
+}
+
+// Redo pops the last undone insertion after the caller inserted it again
+func Redo() (Insertion, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	insertion, err := last(undone, ErrNothingToRedo)
+	if err != nil {
+		return Insertion{}, err
+	}
+	undone = undone[:len(undone)-1]
+	done = append(done, insertion)
+	return insertion, nil
+}
+
+// last returns the top of stack if it was inserted into the focused window.
+// Must be called with mu held.
+func last(stack []Insertion, errEmpty error) (Insertion, error) {
+	if len(stack) == 0 {
+		return Insertion{}, errEmpty
+	}
+	insertion := stack[len(stack)-1]
+	if insertion.Target != CurrentTarget() {
+		return Insertion{}, ErrFocusChanged
+	}
+	return insertion, nil
+}
+
+// Shorten removes chars characters from the end of the last insertion after
+// the caller deleted them from the window, dropping the insertion when
+// nothing of it is left
+func Shorten(chars int) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(done) == 0 || chars <= 0 {
+		return
+	}
+	last := &done[len(done)-1]
+	if last.Return {
+		// Backspace removes the Return pressed after the text first
+		last.Return = false
+		chars--
+	}
+	runes := []rune(last.Text)
+	if chars >= len(runes) {
+		done = done[:len(done)-1]
+		return
+	}
+	last.Text = string(runes[:len(runes)-chars])
+}
@@ -0,0 +1,208 @@
+package itn
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	register(english())
+}
+
+var englishMonths = map[string]string{
+	"january": "January", "february": "February", "march": "March",
+	"april": "April", "may": "May", "june": "June",
+	"july": "July", "august": "August", "september": "September",
+	"october": "October", "november": "November", "december": "December",
+}
+
+func english() *language {
+	l := &language{
+		code: "en",
+		cardinals: map[string]int64{
+			"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4,
+			"five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9,
+			"ten": 10, "eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14,
+			"fifteen": 15, "sixteen": 16, "seventeen": 17, "eighteen": 18, "nineteen": 19,
+			"twenty": 20, "thirty": 30, "forty": 40, "fifty": 50,
+			"sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+		},
+		multipliers: map[string]int64{
+			"hundred": 100, "thousand": 1000, "million": 1000000, "billion": 1000000000,
+			"trillion": 1000000000000,
+		},
+		zeroWords:   map[string]bool{"oh": true},
+		conjunction: "and",
+		minusWords:  map[string]bool{"minus": true, "negative": true},
+		decimalWord: "point",
+		decimalSep:  ".",
+		ordinals: map[string]int64{
+			"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5,
+			"sixth": 6, "seventh": 7, "eighth": 8, "ninth": 9, "tenth": 10,
+			"eleventh": 11, "twelfth": 12, "thirteenth": 13, "fourteenth": 14, "fifteenth": 15,
+			"sixteenth": 16, "seventeenth": 17, "eighteenth": 18, "nineteenth": 19,
+			"twentieth": 20, "thirtieth": 30,
+		},
+	}
+
+	l.units = joinSuffixes(
+		newSuffixes("%", false, false, "percent", "per cent"),
+		newSuffixes("°C", false, false, "degrees celsius", "degree celsius"),
+		newSuffixes("°F", false, false, "degrees fahrenheit", "degree fahrenheit"),
+		newSuffixes("°", false, false, "degrees", "degree"),
+		newSuffixes("km/h", false, true, "kilometers per hour", "kilometres per hour"),
+		newSuffixes("mph", false, true, "miles per hour"),
+		newSuffixes("km", false, true, "kilometers", "kilometer", "kilometres", "kilometre"),
+		newSuffixes("m", false, true, "meters", "meter", "metres", "metre"),
+		newSuffixes("cm", false, true, "centimeters", "centimeter", "centimetres", "centimetre"),
+		newSuffixes("mm", false, true, "millimeters", "millimeter", "millimetres", "millimetre"),
+		newSuffixes("kg", false, true, "kilograms", "kilogram", "kilos"),
+		newSuffixes("g", false, true, "grams", "gram"),
+		newSuffixes("l", false, true, "liters", "liter", "litres", "litre"),
+		newSuffixes("ml", false, true, "milliliters", "milliliter", "millilitres", "millilitre"),
+		newSuffixes("KB", false, true, "kilobytes", "kilobyte"),
+		newSuffixes("MB", false, true, "megabytes", "megabyte"),
+		newSuffixes("GB", false, true, "gigabytes", "gigabyte"),
+		newSuffixes("TB", false, true, "terabytes", "terabyte"),
+	)
+
+	cents := [][]string{{"cents"}, {"cent"}}
+	l.currencies = joinCurrencies(
+		newCurrencies("$", true, false, cents, "dollars", "dollar", "bucks"),
+		newCurrencies("€", true, false, cents, "euros", "euro"),
+	)
+
+	l.matchers = []matcher{matchPhone, matchEnglishTime, matchEnglishDate, matchEnglishOrdinal, matchNumber}
+	return l
+}
+
+// matchEnglishTime rewrites "three thirty pm" -> "3:30 PM", "three oh five" -> "3:05"
+// and "seven o'clock" -> "7:00"
+func matchEnglishTime(s *scanner, i int) (int, string, bool) {
+	hour, ok := s.englishHour(i)
+	if !ok {
+		return 0, "", false
+	}
+
+	if word := s.word(i + 1); (word == "o'clock" || word == "oclock") && s.joined(i+1) {
+		return 2, fmt.Sprintf("%d:00", hour), true
+	}
+
+	minutes, mn, hasMinutes := s.parseMinutes(i + 1)
+	meridiem, an := s.parseMeridiem(i + 1 + mn)
+
+	switch {
+	case hasMinutes && an > 0:
+		return 1 + mn + an, fmt.Sprintf("%d:%02d %s", hour, minutes, meridiem), true
+	case hasMinutes && mn == 2 && s.lang.zeroWords[s.word(i+1)]:
+		// "oh" only introduces minutes
+		return 1 + mn, fmt.Sprintf("%d:%02d", hour, minutes), true
+	case !hasMinutes && an > 0:
+		return 1 + an, fmt.Sprintf("%d %s", hour, meridiem), true
+	}
+	return 0, "", false
+}
+
+// englishHour reads a single-word hour from 1 to 12
+func (s *scanner) englishHour(i int) (int64, bool) {
+	value, n, _ := s.parseCardinal(i)
+	if n != 1 || value < 1 || value > 12 {
+		return 0, false
+	}
+	return value, true
+}
+
+// parseMeridiem reads "am", "pm", "a m" or "p.m."
+func (s *scanner) parseMeridiem(i int) (string, int) {
+	if !s.joined(i) {
+		return "", 0
+	}
+	switch word := s.word(i); word {
+	case "am", "pm":
+		return strings.ToUpper(word), 1
+	case "a", "p":
+		if s.word(i+1) == "m" && s.joinedBy(i+1, ". ") {
+			return strings.ToUpper(word) + "M", 2
+		}
+	}
+	return "", 0
+}
+
+// matchEnglishDate rewrites "may fifth" -> "May 5" and "the fifth of may
+// twenty twenty four" -> "May 5, 2024"
+func matchEnglishDate(s *scanner, i int) (int, string, bool) {
+	if month, ok := englishMonths[s.word(i)]; ok {
+		day, dn, ok := s.englishDay(i + 1)
+		if !ok {
+			return 0, "", false
+		}
+		n := 1 + dn
+		return s.withYear(n, i+n, fmt.Sprintf("%s %d", month, day))
+	}
+
+	start := i
+	if s.word(i) == "the" {
+		i++
+		if !s.joined(i) {
+			return 0, "", false
+		}
+	}
+	day, dn, ok := s.parseOrdinal(i, s.lang.ordinals)
+	if !ok || day > 31 {
+		return 0, "", false
+	}
+	of := i + dn
+	month, ok := englishMonths[s.word(of+1)]
+	if s.word(of) != "of" || !s.joined(of) || !s.joined(of+1) || !ok {
+		return 0, "", false
+	}
+	n := of + 2 - start
+	return s.withYear(n, start+n, fmt.Sprintf("%s %d", month, day))
+}
+
+// englishDay reads a day after a month name: an ordinal or a written number.
+// Spoken cardinals are not accepted so "may one day" is left alone.
+func (s *scanner) englishDay(i int) (int64, int, bool) {
+	if !s.joined(i) {
+		return 0, 0, false
+	}
+	if isDigits(s.word(i)) {
+		day, _, _ := s.parseCardinal(i)
+		return day, 1, day >= 1 && day <= 31
+	}
+	day, n, ok := s.parseOrdinal(i, s.lang.ordinals)
+	return day, n, ok && day <= 31
+}
+
+// withYear appends a year following the date at i
+func (s *scanner) withYear(n, i int, date string) (int, string, bool) {
+	if year, yn, ok := s.parseYear(i); ok {
+		return n + yn, fmt.Sprintf("%s, %d", date, year), true
+	}
+	return n, date, true
+}
+
+// matchEnglishOrdinal rewrites ordinals from eleventh up and compounds:
+// "twenty first century" -> "21st century". "first" and "second" stay words.
+func matchEnglishOrdinal(s *scanner, i int) (int, string, bool) {
+	value, n, ok := s.parseOrdinal(i, s.lang.ordinals)
+	if !ok || (n == 1 && value < 11) {
+		return 0, "", false
+	}
+	return n, fmt.Sprintf("%d%s", value, englishOrdinalSuffix(value)), true
+}
+
+func englishOrdinalSuffix(value int64) string {
+	if value%100 >= 11 && value%100 <= 13 {
+		return "th"
+	}
+	switch value % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+}
@@ -0,0 +1,656 @@
+// Package itn implements rule-based inverse text normalization: spoken
+// numbers, ordinals, dates, times, currencies, units and phone numbers are
+// rewritten in their written form ("twenty five percent" -> "25%",
+// "dwa tysiące złotych" -> "2000 zł").
+package itn
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Normalize rewrites spoken forms in text. Language is an ISO code ("en",
+// "pl"); an empty or unsupported code applies every supported language.
+func Normalize(text, language string) string {
+	for _, lang := range languagesFor(language) {
+		text = lang.normalize(text)
+	}
+	return text
+}
+
+// Supported reports whether language has normalization rules
+func Supported(language string) bool {
+	_, ok := languages[strings.ToLower(language)]
+	return ok
+}
+
+// matcher tries to rewrite the tokens starting at i. It returns the number of
+// tokens consumed and the replacement; ok is false when nothing matched.
+// A matcher may consume tokens without replacing them by returning an empty
+// replacement with ok set.
+type matcher func(s *scanner, i int) (n int, replacement string, ok bool)
+
+// suffix is a word sequence written as a symbol after (or before) a number
+type suffix struct {
+	words  []string
+	symbol string
+	prefix bool // symbol goes before the number ("$5")
+	space  bool // symbol is separated by a space ("5 km")
+}
+
+// currency is a suffix with an optional minor unit ("dollars and five cents")
+type currency struct {
+	suffix
+	minor [][]string
+}
+
+// language holds the word tables and matchers of one language
+type language struct {
+	code        string
+	cardinals   map[string]int64
+	multipliers map[string]int64
+	// bareMultipliers allows a multiplier to start a number ("tysiąc" = 1000)
+	bareMultipliers bool
+	// zeroWords are extra words read as 0 in digit sequences ("oh")
+	zeroWords   map[string]bool
+	conjunction string
+	minusWords  map[string]bool
+	decimalWord string
+	decimalSep  string
+	ordinals    map[string]int64
+	units       []suffix
+	currencies  []currency
+	matchers    []matcher
+}
+
+var languages = map[string]*language{}
+
+func register(lang *language) {
+	sortSuffixes(lang.units)
+	languages[lang.code] = lang
+}
+
+func languagesFor(code string) []*language {
+	if lang, ok := languages[strings.ToLower(code)]; ok {
+		return []*language{lang}
+	}
+	return []*language{languages["en"], languages["pl"]}
+}
+
+// normalize applies the language matchers left to right
+func (l *language) normalize(text string) string {
+	s := &scanner{text: text, tokens: tokenize(text), lang: l}
+
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(s.tokens); {
+		n, replacement, ok := s.match(i)
+		if !ok {
+			i++
+			continue
+		}
+		if replacement != "" {
+			b.WriteString(text[last:s.tokens[i].start])
+			b.WriteString(replacement)
+			last = s.tokens[i+n-1].end
+		}
+		i += n
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// token is a lowercase word with its byte span in the source text
+type token struct {
+	word  string
+	start int
+	end   int
+}
+
+// tokenize splits text into lowercase words with their byte spans
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\''
+		if isWordRune && start < 0 {
+			start = i
+		} else if !isWordRune && start >= 0 {
+			tokens = append(tokens, token{word: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{word: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// scanner gives matchers access to tokens and the text between them
+type scanner struct {
+	text   string
+	tokens []token
+	lang   *language
+}
+
+func (s *scanner) match(i int) (int, string, bool) {
+	for _, m := range s.lang.matchers {
+		if n, replacement, ok := m(s, i); ok {
+			return n, replacement, true
+		}
+	}
+	return 0, "", false
+}
+
+// word returns the token at i, or "" past the end
+func (s *scanner) word(i int) string {
+	if i < 0 || i >= len(s.tokens) {
+		return ""
+	}
+	return s.tokens[i].word
+}
+
+// joined reports whether token i directly follows token i-1, separated only
+// by spaces or hyphens ("twenty-five")
+func (s *scanner) joined(i int) bool {
+	return s.joinedBy(i, " -")
+}
+
+// joinedBy reports whether only characters from cutset separate tokens i-1 and i
+func (s *scanner) joinedBy(i int, cutset string) bool {
+	if i <= 0 || i >= len(s.tokens) {
+		return false
+	}
+	gap := s.text[s.tokens[i-1].end:s.tokens[i].start]
+	return gap != "" && strings.Trim(gap, cutset) == ""
+}
+
+// isDigits reports whether word is a written number
+func isDigits(word string) bool {
+	if word == "" {
+		return false
+	}
+	for _, r := range word {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// digit returns the value of a single spoken or written digit at i
+func (s *scanner) digit(i int) (int64, bool) {
+	word := s.word(i)
+	if len(word) == 1 && isDigits(word) {
+		return int64(word[0] - '0'), true
+	}
+	if s.lang.zeroWords[word] {
+		return 0, true
+	}
+	if v, ok := s.lang.cardinals[word]; ok && v < 10 {
+		return v, true
+	}
+	return 0, false
+}
+
+// parseCardinal reads a cardinal number starting at i: either a written
+// number or a run of number words ("two hundred and five", "dwa tysiące").
+// stoppedAtNumber is set when the run ended on a number word that could not
+// be combined ("three thirty"), which usually means a sequence rather than
+// a single number.
+func (s *scanner) parseCardinal(i int) (value int64, n int, stoppedAtNumber bool) {
+	if isDigits(s.word(i)) {
+		v, err := strconv.ParseInt(s.word(i), 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		return v, 1, false
+	}
+
+	var total, current int64
+	lastMultiplier := int64(0)
+	for j := i; j < len(s.tokens); j++ {
+		if j > i && !s.joined(j) {
+			break
+		}
+		word := s.word(j)
+
+		if v, ok := s.lang.cardinals[word]; ok {
+			// After "zero" nothing combines: "zero five" is a digit sequence
+			if (n > 0 && total+current == 0) || !canAdd(current, v) {
+				return total + current, n, true
+			}
+			current += v
+			n = j - i + 1
+			continue
+		}
+
+		if m, ok := s.lang.multipliers[word]; ok {
+			if n == 0 && !s.lang.bareMultipliers {
+				// "a hundred people" stays spelled out
+				break
+			}
+			// A scale word that cannot be joined ("billion trillion", "hundred
+			// hundred") makes the whole run ambiguous, so it is left alone
+			if m == 100 {
+				if current >= 100 {
+					return total + current, n, true
+				}
+				current = max(current, 1) * 100
+			} else {
+				if lastMultiplier != 0 && m >= lastMultiplier {
+					return total + current, n, true
+				}
+				total += max(current, 1) * m
+				current = 0
+				lastMultiplier = m
+			}
+			n = j - i + 1
+			continue
+		}
+
+		// "one hundred and five"
+		if word == s.lang.conjunction && n > 0 && (current == 0 || current%100 == 0) {
+			if _, ok := s.lang.cardinals[s.word(j+1)]; ok && s.joined(j+1) {
+				continue
+			}
+		}
+		break
+	}
+	return total + current, n, false
+}
+
+// canAdd reports whether cardinal v can extend current ("twenty" + "five",
+// "sto" + "dwadzieścia") rather than start a new number
+func canAdd(current, v int64) bool {
+	rest := current % 1000
+	switch {
+	case current == 0:
+		return true
+	case rest == 0:
+		return true
+	case rest%100 == 0:
+		return v < 100
+	case rest%10 == 0 && rest%100 >= 20:
+		return v < 10
+	}
+	return false
+}
+
+// number is a parsed cardinal with optional sign and fraction
+type number struct {
+	value    int64
+	fraction string
+	negative bool
+	n        int
+	spoken   bool
+}
+
+// parseNumber reads a signed cardinal with an optional decimal part
+func (s *scanner) parseNumber(i int) (number, bool) {
+	var num number
+	start := i
+	if s.lang.minusWords[s.word(i)] {
+		num.negative = true
+		i++
+		if !s.joined(i) {
+			return num, false
+		}
+	}
+
+	value, n, stopped := s.parseCardinal(i)
+	if n == 0 || stopped {
+		return num, false
+	}
+	num.value = value
+	num.spoken = !isDigits(s.word(i))
+	i += n
+
+	if s.word(i) == s.lang.decimalWord && s.joined(i) && s.joined(i+1) {
+		if fraction, fn := s.parseFraction(i + 1); fn > 0 {
+			num.fraction = fraction
+			i += 1 + fn
+		}
+	}
+
+	num.n = i - start
+	return num, true
+}
+
+// parseFraction reads the digits after a decimal word: either single digits
+// ("point two five") or, when the first word is not a digit, a cardinal
+// ("trzy przecinek czternaście")
+func (s *scanner) parseFraction(i int) (string, int) {
+	var digits strings.Builder
+	n := 0
+	for j := i; j < len(s.tokens); j++ {
+		if j > i && !s.joined(j) {
+			break
+		}
+		d, ok := s.digit(j)
+		if !ok {
+			break
+		}
+		digits.WriteString(strconv.FormatInt(d, 10))
+		n++
+	}
+	if n > 1 || (n == 1 && !s.startsCardinal(i+1)) {
+		return digits.String(), n
+	}
+
+	value, cn, _ := s.parseCardinal(i)
+	if cn == 0 {
+		return "", 0
+	}
+	return strconv.FormatInt(value, 10), cn
+}
+
+// startsCardinal reports whether a joined cardinal word starts at i
+func (s *scanner) startsCardinal(i int) bool {
+	if !s.joined(i) {
+		return false
+	}
+	_, ok := s.lang.cardinals[s.word(i)]
+	return ok
+}
+
+// format writes a number with the language decimal separator
+func (l *language) format(num number) string {
+	var b strings.Builder
+	if num.negative {
+		b.WriteString("-")
+	}
+	b.WriteString(strconv.FormatInt(num.value, 10))
+	if num.fraction != "" {
+		b.WriteString(l.decimalSep)
+		b.WriteString(num.fraction)
+	}
+	return b.String()
+}
+
+// parseOrdinal reads an ordinal from forms, including compounds of a tens
+// word and a unit ordinal ("twenty first", "dwudziestego piątego")
+func (s *scanner) parseOrdinal(i int, forms map[string]int64) (int64, int, bool) {
+	word := s.word(i)
+
+	value, isOrdinal := forms[word]
+	if !isOrdinal {
+		// English compounds start with a cardinal: "twenty first"
+		value = s.lang.cardinals[word]
+	}
+	if value >= 20 && value%10 == 0 && s.joined(i+1) {
+		if unit, ok := forms[s.word(i+1)]; ok && unit < 10 {
+			return value + unit, 2, true
+		}
+	}
+
+	if isOrdinal {
+		return value, 1, true
+	}
+	return 0, 0, false
+}
+
+// matchSuffix returns the suffix whose words follow at i
+func (s *scanner) matchSuffix(i int, suffixes []suffix) (*suffix, int) {
+	for k := range suffixes {
+		if s.matchWords(i, suffixes[k].words) {
+			return &suffixes[k], len(suffixes[k].words)
+		}
+	}
+	return nil, 0
+}
+
+// matchWords reports whether words follow at i, each joined to the previous token
+func (s *scanner) matchWords(i int, words []string) bool {
+	for k, word := range words {
+		if s.word(i+k) != word || !s.joined(i+k) {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+// attach writes a formatted number with its suffix symbol
+func (sf *suffix) attach(formatted string) string {
+	switch {
+	case sf.prefix && strings.HasPrefix(formatted, "-"):
+		return "-" + sf.symbol + formatted[1:]
+	case sf.prefix:
+		return sf.symbol + formatted
+	case sf.space:
+		return formatted + " " + sf.symbol
+	}
+	return formatted + sf.symbol
+}
+
+// matchNumber rewrites cardinals, decimals, currencies, units and percents.
+// Lone small numbers without a unit stay spelled out ("one of them").
+func matchNumber(s *scanner, i int) (int, string, bool) {
+	num, ok := s.parseNumber(i)
+	if !ok {
+		return skipSequence(s, i)
+	}
+	next := i + num.n
+
+	if n, text, ok := s.matchCurrency(num, next); ok {
+		return num.n + n, text, true
+	}
+	if sf, n := s.matchSuffix(next, s.lang.units); sf != nil {
+		return num.n + n, sf.attach(s.lang.format(num)), true
+	}
+
+	if !num.spoken {
+		return num.n, "", true
+	}
+	if num.n == 1 && num.value < 10 {
+		return 0, "", false
+	}
+	return num.n, s.lang.format(num), true
+}
+
+// skipSequence leaves a run of number words that do not form one number
+// ("three thirty", "two three") untouched
+func skipSequence(s *scanner, i int) (int, string, bool) {
+	_, n, stopped := s.parseCardinal(i)
+	if !stopped {
+		return 0, "", false
+	}
+	j := i + n
+	for {
+		_, cn, _ := s.parseCardinal(j)
+		if cn == 0 || !s.joined(j) || isDigits(s.word(j)) {
+			break
+		}
+		j += cn
+	}
+	return j - i, "", true
+}
+
+// matchCurrency reads a currency name after a number, with optional minor units
+func (s *scanner) matchCurrency(num number, i int) (int, string, bool) {
+	for k := range s.lang.currencies {
+		c := &s.lang.currencies[k]
+		if !s.matchWords(i, c.words) {
+			continue
+		}
+		n := len(c.words)
+
+		if num.fraction == "" {
+			if minor, mn := s.matchMinor(c, i+n); mn > 0 {
+				num.fraction = minor
+				n += mn
+			}
+		}
+		return n, c.attach(s.lang.format(num)), true
+	}
+	return 0, "", false
+}
+
+// matchMinor reads "[and] <cardinal> <minor unit>" and returns the two-digit fraction
+func (s *scanner) matchMinor(c *currency, i int) (string, int) {
+	start := i
+	if s.word(i) == s.lang.conjunction && s.joined(i) {
+		i++
+	}
+	if !s.joined(i) {
+		return "", 0
+	}
+	value, n, stopped := s.parseCardinal(i)
+	if n == 0 || stopped || value > 99 {
+		return "", 0
+	}
+	for _, words := range c.minor {
+		if s.matchWords(i+n, words) {
+			return twoDigits(value), i + n + len(words) - start
+		}
+	}
+	return "", 0
+}
+
+// matchPhone rewrites runs of at least seven spoken digits as a phone number
+func matchPhone(s *scanner, i int) (int, string, bool) {
+	start := i
+	plus := false
+	if s.word(i) == "plus" {
+		plus = true
+		i++
+	}
+
+	var digits strings.Builder
+	spoken := false
+	for j := i; j < len(s.tokens); j++ {
+		if j > start && !s.joined(j) {
+			break
+		}
+		d, ok := s.digit(j)
+		if !ok {
+			break
+		}
+		if !isDigits(s.word(j)) {
+			spoken = true
+		}
+		digits.WriteString(strconv.FormatInt(d, 10))
+	}
+
+	number := digits.String()
+	if len(number) < 7 || !spoken {
+		return 0, "", false
+	}
+	return len(number) + (i - start), formatPhone(number, plus), true
+}
+
+// formatPhone groups nine-digit numbers (optionally with a country code) in threes
+func formatPhone(number string, plus bool) string {
+	prefix := ""
+	if plus {
+		prefix = "+"
+	}
+	switch {
+	case len(number) == 9:
+		return prefix + number[:3] + " " + number[3:6] + " " + number[6:]
+	case plus && len(number) == 11:
+		return prefix + number[:2] + " " + number[2:5] + " " + number[5:8] + " " + number[8:]
+	}
+	return prefix + number
+}
+
+func twoDigits(v int64) string {
+	if v < 10 {
+		return "0" + strconv.FormatInt(v, 10)
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// parseMinutes reads minutes after an hour: "oh five"/"zero pięć" or a cardinal up to 59
+func (s *scanner) parseMinutes(i int) (int64, int, bool) {
+	if !s.joined(i) {
+		return 0, 0, false
+	}
+	if d, ok := s.digit(i); ok && d == 0 && s.joined(i+1) {
+		if d2, ok := s.digit(i + 1); ok {
+			return d2, 2, true
+		}
+	}
+	value, n, stopped := s.parseCardinal(i)
+	if n == 0 || stopped || value > 59 {
+		return 0, 0, false
+	}
+	return value, n, true
+}
+
+// parseYear reads a year after a date: a cardinal ("two thousand twenty four")
+// or a pair of two-digit numbers ("nineteen ninety nine")
+func (s *scanner) parseYear(i int) (int64, int, bool) {
+	if !s.joined(i) {
+		return 0, 0, false
+	}
+	value, n, stopped := s.parseCardinal(i)
+	if n == 0 {
+		return 0, 0, false
+	}
+	if value >= 1000 && !stopped {
+		return value, n, true
+	}
+	if value >= 10 && value < 100 && stopped {
+		low, ln, lowStopped := s.parseCardinal(i + n)
+		if ln > 0 && !lowStopped && low < 100 {
+			return value*100 + low, n + ln, true
+		}
+	}
+	return 0, 0, false
+}
+
+// splitWords lowercases a phrase and splits it into words
+func splitWords(phrase string) []string {
+	var words []string
+	for _, t := range tokenize(phrase) {
+		words = append(words, t.word)
+	}
+	return words
+}
+
+// sortSuffixes orders suffixes so multi-word names win over their prefixes
+func sortSuffixes(suffixes []suffix) {
+	for i := 1; i < len(suffixes); i++ {
+		for j := i; j > 0 && len(suffixes[j].words) > len(suffixes[j-1].words); j-- {
+			suffixes[j], suffixes[j-1] = suffixes[j-1], suffixes[j]
+		}
+	}
+}
+
+// newSuffixes builds suffixes from spoken forms sharing one symbol
+func newSuffixes(symbol string, prefix, space bool, spoken ...string) []suffix {
+	suffixes := make([]suffix, 0, len(spoken))
+	for _, phrase := range spoken {
+		suffixes = append(suffixes, suffix{words: splitWords(phrase), symbol: symbol, prefix: prefix, space: space})
+	}
+	return suffixes
+}
+
+// joinSuffixes concatenates suffix groups
+func joinSuffixes(groups ...[]suffix) []suffix {
+	var all []suffix
+	for _, group := range groups {
+		all = append(all, group...)
+	}
+	return all
+}
+
+// newCurrencies builds currencies from spoken names sharing one symbol and minor unit
+func newCurrencies(symbol string, prefix, space bool, minor [][]string, spoken ...string) []currency {
+	var currencies []currency
+	for _, sf := range newSuffixes(symbol, prefix, space, spoken...) {
+		currencies = append(currencies, currency{suffix: sf, minor: minor})
+	}
+	return currencies
+}
+
+// joinCurrencies concatenates currency groups
+func joinCurrencies(groups ...[]currency) []currency {
+	var all []currency
+	for _, group := range groups {
+		all = append(all, group...)
+	}
+	return all
+}
@@ -0,0 +1,110 @@
+package itn
+
+import "testing"
+
+func TestNormalizeEnglish(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		// Cardinals and decimals
+		{"twenty five", "25"},
+		{"two hundred and five", "205"},
+		{"two thousand twenty four", "2024"},
+		{"nine hundred ninety nine trillion nine hundred ninety nine billion", "999999000000000"},
+		{"minus three point five", "-3.5"},
+		{"one of them", "one of them"},
+		{"a hundred people", "a hundred people"},
+		// Scale words in an impossible order are left alone
+		{"nine hundred ninety nine trillion nine hundred ninety nine billion trillion", "nine hundred ninety nine trillion nine hundred ninety nine billion trillion"},
+		{"two million three million", "two million three million"},
+		{"five hundred hundred", "five hundred hundred"},
+		// Ordinals
+		{"the twenty first century", "the 21st century"},
+		{"his thirteenth birthday", "his 13th birthday"},
+		{"first come first served", "first come first served"},
+		// Dates
+		{"may fifth twenty twenty four", "May 5, 2024"},
+		{"the fifth of may", "May 5"},
+		{"may one day", "may one day"},
+		// Times
+		{"meet me at three thirty pm", "meet me at 3:30 PM"},
+		{"seven o'clock", "7:00"},
+		{"three oh five", "3:05"},
+		{"at nine a.m.", "at 9 AM."},
+		{"half past three", "half past three"},
+		{"three thirty", "three thirty"},
+		// Currencies, units and percents
+		{"twenty five percent", "25%"},
+		{"it costs five dollars and twenty cents", "it costs $5.20"},
+		{"ten euros", "€10"},
+		{"minus three point five degrees celsius", "-3.5°C"},
+		{"five kilometers", "5 km"},
+		{"ten megabytes", "10 MB"},
+		{"sixty miles per hour", "60 mph"},
+		// Phone numbers
+		{"call plus four eight one two three four five six seven eight nine", "call +48 123 456 789"},
+		{"five oh one two three four five six seven", "501 234 567"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in, "en"); got != tt.want {
+			t.Errorf("Normalize(%q, en) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizePolish(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		// Cardinals and decimals
+		{"dwadzieścia pięć", "25"},
+		{"tysiąc", "1000"},
+		{"dwa miliony trzysta tysięcy", "2300000"},
+		{"trzy przecinek czternaście", "3,14"},
+		{"jeden z nich", "jeden z nich"},
+		{"zero pięć", "zero pięć"},
+		// Scale words in an impossible order are left alone
+		{"dwa miliony trzy miliony", "dwa miliony trzy miliony"},
+		{"dziewięćset miliardów bilionów", "dziewięćset miliardów bilionów"},
+		// Dates
+		{"piątego maja dwa tysiące dwudziestego czwartego", "5 maja 2024"},
+		{"dwudziestego pierwszego grudnia", "21 grudnia"},
+		// Full hours
+		{"o piętnastej trzydzieści", "o 15:30"},
+		{"do drugiej", "do 2:00"},
+		{"od ósmej do szesnastej", "od 8:00 do 16:00"},
+		{"godzina piąta", "godzina 5:00"},
+		{"o siódmej rano", "o 7:00 rano"},
+		{"o drugiej połowie", "o drugiej połowie"},
+		// Hours counted from or to another hour stay spoken
+		{"wpół do trzeciej", "wpół do trzeciej"},
+		{"spotkajmy się o wpół do trzeciej", "spotkajmy się o wpół do trzeciej"},
+		{"kwadrans do trzeciej", "kwadrans do trzeciej"},
+		{"dziesięć do trzeciej", "10 do trzeciej"},
+		// Currencies, units and percents
+		{"dwa tysiące złotych", "2000 zł"},
+		{"pięć złotych i dwadzieścia groszy", "5,20 zł"},
+		{"dwadzieścia pięć procent", "25%"},
+		{"sto dwadzieścia kilometrów na godzinę", "120 km/h"},
+		{"minus pięć stopni", "-5°"},
+		// Phone numbers
+		{"numer pięć zero jeden dwa trzy cztery pięć sześć siedem", "numer 501 234 567"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in, "pl"); got != tt.want {
+			t.Errorf("Normalize(%q, pl) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeUnknownLanguage(t *testing.T) {
+	// Without a supported language both rule sets apply
+	if got, want := Normalize("twenty five percent, dwa tysiące złotych", ""), "25%, 2000 zł"; got != want {
+		t.Errorf("Normalize() = %q, want %q", got, want)
+	}
+	if Supported("de") || !Supported("PL") {
+		t.Error("Supported() reports the wrong languages")
+	}
+}
@@ -0,0 +1,218 @@
+package itn
+
+import "fmt"
+
+func init() {
+	register(polish())
+}
+
+var polishMonths = map[string]bool{
+	"stycznia": true, "lutego": true, "marca": true, "kwietnia": true,
+	"maja": true, "czerwca": true, "lipca": true, "sierpnia": true,
+	"września": true, "października": true, "listopada": true, "grudnia": true,
+}
+
+// polishTimeTriggers introduce an hour. Weak triggers ("o drugiej") also start
+// ordinary phrases ("o drugiej połowie"), so they need minutes or a time-of-day
+// word after the hour; strong ones ("godzina piąta") do not.
+var polishTimeTriggers = map[string]bool{
+	"o": false, "od": false, "do": false, "około": false,
+	"godzina": true, "godzinie": true, "godz": true,
+}
+
+// polishRelativeTimeWords before a trigger make the hour relative ("wpół do
+// trzeciej" is 2:30, "kwadrans do trzeciej" 2:45), so it is not a full hour
+var polishRelativeTimeWords = map[string]bool{
+	"wpół": true, "pół": true, "kwadrans": true, "minut": true, "minuty": true, "minutę": true,
+}
+
+// polishHourFollowers may follow a bare hour after a weak trigger
+var polishHourFollowers = map[string]bool{
+	"rano": true, "wieczorem": true, "w": true, "po": true, "i": true,
+	"a": true, "lub": true, "albo": true, "do": true, "ale": true,
+}
+
+// ordinalStem is the stem of a Polish ordinal and its inflection class
+type ordinalStem struct {
+	stem  string
+	value int64
+	class int
+}
+
+const (
+	hardStem  = iota // piąt-y, piąt-ego, piąt-a, piąt-ej
+	velarStem        // drug-i, drug-iego, drug-a, drug-iej
+	softStem         // trzeci, trzeci-ego, trzeci-a, trzeci-ej
+)
+
+var polishOrdinalStems = []ordinalStem{
+	{"pierwsz", 1, hardStem}, {"drug", 2, velarStem}, {"trzeci", 3, softStem},
+	{"czwart", 4, hardStem}, {"piąt", 5, hardStem}, {"szóst", 6, hardStem},
+	{"siódm", 7, hardStem}, {"ósm", 8, hardStem}, {"dziewiąt", 9, hardStem},
+	{"dziesiąt", 10, hardStem}, {"jedenast", 11, hardStem}, {"dwunast", 12, hardStem},
+	{"trzynast", 13, hardStem}, {"czternast", 14, hardStem}, {"piętnast", 15, hardStem},
+	{"szesnast", 16, hardStem}, {"siedemnast", 17, hardStem}, {"osiemnast", 18, hardStem},
+	{"dziewiętnast", 19, hardStem}, {"dwudziest", 20, hardStem}, {"trzydziest", 30, hardStem},
+	{"czterdziest", 40, hardStem}, {"pięćdziesiąt", 50, hardStem}, {"sześćdziesiąt", 60, hardStem},
+	{"siedemdziesiąt", 70, hardStem}, {"osiemdziesiąt", 80, hardStem}, {"dziewięćdziesiąt", 90, hardStem},
+}
+
+// ordinal endings per class: all forms, and the feminine forms used for hours
+var (
+	ordinalEndings = [][]string{
+		hardStem:  {"y", "ego", "emu", "ym", "a", "ej", "ą", "e", "ych"},
+		velarStem: {"i", "iego", "iemu", "im", "a", "iej", "ą", "ie", "ich"},
+		softStem:  {"", "ego", "emu", "m", "a", "ej", "ą", "e", "ch"},
+	}
+	hourEndings = [][]string{
+		hardStem:  {"a", "ej", "ą"},
+		velarStem: {"a", "iej", "ą"},
+		softStem:  {"a", "ej", "ą"},
+	}
+)
+
+// polishHours holds feminine ordinal forms, which name hours ("piętnasta")
+var polishHours = inflectOrdinals(hourEndings)
+
+func polish() *language {
+	l := &language{
+		code: "pl",
+		cardinals: map[string]int64{
+			"zero": 0, "jeden": 1, "jedna": 1, "jedno": 1, "dwa": 2, "dwie": 2,
+			"trzy": 3, "cztery": 4, "pięć": 5, "sześć": 6, "siedem": 7, "osiem": 8, "dziewięć": 9,
+			"dziesięć": 10, "jedenaście": 11, "dwanaście": 12, "trzynaście": 13, "czternaście": 14,
+			"piętnaście": 15, "szesnaście": 16, "siedemnaście": 17, "osiemnaście": 18, "dziewiętnaście": 19,
+			"dwadzieścia": 20, "trzydzieści": 30, "czterdzieści": 40, "pięćdziesiąt": 50,
+			"sześćdziesiąt": 60, "siedemdziesiąt": 70, "osiemdziesiąt": 80, "dziewięćdziesiąt": 90,
+			"sto": 100, "dwieście": 200, "trzysta": 300, "czterysta": 400, "pięćset": 500,
+			"sześćset": 600, "siedemset": 700, "osiemset": 800, "dziewięćset": 900,
+		},
+		multipliers: map[string]int64{
+			"tysiąc": 1000, "tysiące": 1000, "tysięcy": 1000, "tysiąca": 1000,
+			"milion": 1000000, "miliony": 1000000, "milionów": 1000000, "miliona": 1000000,
+			"miliard": 1000000000, "miliardy": 1000000000, "miliardów": 1000000000, "miliarda": 1000000000,
+			"bilion": 1000000000000, "biliony": 1000000000000, "bilionów": 1000000000000, "biliona": 1000000000000,
+		},
+		bareMultipliers: true,
+		conjunction:     "i",
+		minusWords:      map[string]bool{"minus": true},
+		decimalWord:     "przecinek",
+		decimalSep:      ",",
+		ordinals:        inflectOrdinals(ordinalEndings),
+	}
+
+	l.units = joinSuffixes(
+		newSuffixes("%", false, false, "procent", "procenta", "procentów"),
+		newSuffixes("°C", false, false, "stopni celsjusza", "stopnie celsjusza", "stopień celsjusza", "stopnia celsjusza"),
+		newSuffixes("°", false, false, "stopni", "stopnie", "stopień", "stopnia"),
+		newSuffixes("km/h", false, true, "kilometrów na godzinę", "kilometry na godzinę", "kilometr na godzinę"),
+		newSuffixes("km", false, true, "kilometrów", "kilometry", "kilometr", "kilometra"),
+		newSuffixes("m", false, true, "metrów", "metry", "metr", "metra"),
+		newSuffixes("cm", false, true, "centymetrów", "centymetry", "centymetr", "centymetra"),
+		newSuffixes("mm", false, true, "milimetrów", "milimetry", "milimetr", "milimetra"),
+		newSuffixes("kg", false, true, "kilogramów", "kilogramy", "kilogram", "kilograma", "kilo"),
+		newSuffixes("g", false, true, "gramów", "gramy", "gram", "grama"),
+		newSuffixes("l", false, true, "litrów", "litry", "litr", "litra"),
+		newSuffixes("ml", false, true, "mililitrów", "mililitry", "mililitr", "mililitra"),
+		newSuffixes("KB", false, true, "kilobajtów", "kilobajty", "kilobajt"),
+		newSuffixes("MB", false, true, "megabajtów", "megabajty", "megabajt"),
+		newSuffixes("GB", false, true, "gigabajtów", "gigabajty", "gigabajt"),
+		newSuffixes("TB", false, true, "terabajtów", "terabajty", "terabajt"),
+	)
+
+	cents := [][]string{{"centów"}, {"centy"}, {"cent"}}
+	l.currencies = joinCurrencies(
+		newCurrencies("zł", false, true, [][]string{{"groszy"}, {"grosze"}, {"grosz"}},
+			"złotych", "złote", "złoty", "złotego"),
+		newCurrencies("€", false, true, cents, "euro"),
+		newCurrencies("$", false, true, cents, "dolarów", "dolary", "dolar", "dolara"),
+	)
+
+	l.matchers = []matcher{matchPhone, matchPolishTime, matchPolishDate, matchNumber}
+	return l
+}
+
+// inflectOrdinals builds a form -> value table from the ordinal stems
+func inflectOrdinals(endings [][]string) map[string]int64 {
+	forms := make(map[string]int64)
+	for _, stem := range polishOrdinalStems {
+		for _, ending := range endings[stem.class] {
+			forms[stem.stem+ending] = stem.value
+		}
+	}
+	return forms
+}
+
+// matchPolishTime rewrites "o piętnastej trzydzieści" -> "o 15:30" and
+// "do drugiej" -> "do 2:00". The trigger word is kept.
+func matchPolishTime(s *scanner, i int) (int, string, bool) {
+	strong, isTrigger := polishTimeTriggers[s.word(i)]
+	if !isTrigger || !s.joined(i+1) || s.relativeHour(i) {
+		return 0, "", false
+	}
+	hour, hn, ok := s.parseOrdinal(i+1, polishHours)
+	if !ok || hour < 1 || hour > 24 {
+		return 0, "", false
+	}
+
+	next := i + 1 + hn
+	minutes, mn, hasMinutes := s.parseMinutes(next)
+	if !strong && !hasMinutes && s.joined(next) && !polishHourFollowers[s.word(next)] {
+		return 0, "", false
+	}
+	trigger := s.text[s.tokens[i].start:s.tokens[i+1].start]
+	return 1 + hn + mn, fmt.Sprintf("%s%d:%02d", trigger, hour, minutes), true
+}
+
+// relativeHour reports whether the trigger at i counts from an hour rather
+// than naming it: "wpół do trzeciej", "kwadrans do", "dziesięć do"
+func (s *scanner) relativeHour(i int) bool {
+	if !s.joined(i) {
+		return false
+	}
+	prev := s.word(i - 1)
+	if polishRelativeTimeWords[prev] {
+		return true
+	}
+	// Minutes counted to the hour: "dziesięć do trzeciej"
+	_, isNumber := s.lang.cardinals[prev]
+	return isNumber && s.word(i) == "do"
+}
+
+// matchPolishDate rewrites "piątego maja dwa tysiące dwudziestego czwartego"
+// -> "5 maja 2024"
+func matchPolishDate(s *scanner, i int) (int, string, bool) {
+	day, dn, ok := s.parseOrdinal(i, s.lang.ordinals)
+	if !ok || day < 1 || day > 31 {
+		return 0, "", false
+	}
+	month := i + dn
+	if !polishMonths[s.word(month)] || !s.joined(month) {
+		return 0, "", false
+	}
+
+	n := dn + 1
+	date := fmt.Sprintf("%d %s", day, s.tokens[month].word)
+	if year, yn, ok := s.polishYear(month + 1); ok {
+		return n + yn, fmt.Sprintf("%s %d", date, year), true
+	}
+	return n, date, true
+}
+
+// polishYear reads a cardinal year or a cardinal thousands part followed by
+// an ordinal ("dwa tysiące dwudziestego czwartego")
+func (s *scanner) polishYear(i int) (int64, int, bool) {
+	if !s.joined(i) {
+		return 0, 0, false
+	}
+	value, n, stopped := s.parseCardinal(i)
+	if n == 0 || stopped || value < 1000 {
+		return 0, 0, false
+	}
+	if s.joined(i + n) {
+		if rest, rn, ok := s.parseOrdinal(i+n, s.lang.ordinals); ok && value%100 == 0 {
+			return value + rest, n + rn, true
+		}
+	}
+	return value, n, true
+}
@@ -10,6 +10,9 @@ import (
 
 	"github.com/dooshek/voicify/internal/audio"
 	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/postprocess"
+	"github.com/dooshek/voicify/internal/spelling"
+	"github.com/dooshek/voicify/internal/state"
 	"github.com/dooshek/voicify/internal/transcriptionrouter"
 	"github.com/dooshek/voicify/internal/types"
 )
@@ -98,6 +101,7 @@ func (b *BaseMonitor) handleRecordingToggle() {
 
 	if !b.recorder.IsRecording() {
 		logger.Debugf("Starting recording")
+		state.Get().SetRecordingMode(types.RecordingModeKeyboard)
 		b.recorder.Start()
 	} else {
 		logger.Debugf("Stopping recording")
@@ -112,6 +116,10 @@ func (b *BaseMonitor) handleRecordingToggle() {
 			return
 		}
 
+		if !spelling.Requested(transcription) {
+			transcription = postprocess.Apply(transcription)
+		}
+
 		router := transcriptionrouter.New(transcription)
 		if err := router.Route(transcription); err != nil {
 			logger.Errorf("Error routing transcription: %v", err)
@@ -0,0 +1,42 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// FakeProvider answers completions without calling an API, for offline runs
+// such as testing the router evaluation harness. Token usage is estimated at
+// four characters per token.
+type FakeProvider struct {
+	// Respond returns the completion for a request
+	Respond func(req CompletionRequest) (string, error)
+}
+
+// TranscribeAudio is not supported by the fake provider
+func (p *FakeProvider) TranscribeAudio(ctx context.Context, filename string, reader AudioReader) (string, error) {
+	return "", fmt.Errorf("fake provider cannot transcribe audio")
+}
+
+// Completion returns the canned response
+func (p *FakeProvider) Completion(ctx context.Context, req CompletionRequest) (string, error) {
+	response, _, err := p.CompletionWithUsage(ctx, req)
+	return response, err
+}
+
+// CompletionWithUsage returns the canned response and its estimated token usage
+func (p *FakeProvider) CompletionWithUsage(ctx context.Context, req CompletionRequest) (string, Usage, error) {
+	if err := ctx.Err(); err != nil {
+		return "", Usage{}, err
+	}
+	response, err := p.Respond(req)
+	if err != nil {
+		return "", Usage{}, err
+	}
+
+	promptChars := 0
+	for _, msg := range req.Messages {
+		promptChars += len(msg.Content)
+	}
+	return response, Usage{PromptTokens: promptChars / 4, CompletionTokens: len(response) / 4}, nil
+}
@@ -39,6 +39,10 @@ type groqResponse struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
 	} `json:"error,omitempty"`
@@ -77,12 +81,12 @@ func (p *GroqProvider) TranscribeAudio(ctx context.Context, filename string, rea
 	}
 
 	// Add the model field
-	if err := writer.WriteField("model", state.Get().Config.LLM.Transcription.Model); err != nil {
+	if err := writer.WriteField("model", state.Get().GetConfig().LLM.Transcription.Model); err != nil {
 		return "", fmt.Errorf("error writing model field: %w", err)
 	}
 
 	// Add language to multipart form if specified
-	if lang := state.Get().Config.LLM.Transcription.Language; lang != "" {
+	if lang := state.Get().GetConfig().LLM.Transcription.Language; lang != "" {
 		if err := writer.WriteField("language", lang); err != nil {
 			return "", fmt.Errorf("error writing language field: %w", err)
 		}
@@ -138,6 +142,12 @@ func (p *GroqProvider) TranscribeAudio(ctx context.Context, filename string, rea
 
 // Completion sends a completion request to Groq API
 func (p *GroqProvider) Completion(ctx context.Context, req CompletionRequest) (string, error) {
+	response, _, err := p.CompletionWithUsage(ctx, req)
+	return response, err
+}
+
+// CompletionWithUsage sends a completion request to Groq API and reports its token usage
+func (p *GroqProvider) CompletionWithUsage(ctx context.Context, req CompletionRequest) (string, Usage, error) {
 	logger.Debugf("Sending completion request with model: %s", req.Model)
 
 	if req.MaxTokens == 0 {
@@ -165,12 +175,12 @@ func (p *GroqProvider) Completion(ctx context.Context, req CompletionRequest) (s
 
 	jsonData, err := json.Marshal(groqReq)
 	if err != nil {
-		return "", fmt.Errorf("error marshaling request: %w", err)
+		return "", Usage{}, fmt.Errorf("error marshaling request: %w", err)
 	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("error creating request: %w", err)
+		return "", Usage{}, fmt.Errorf("error creating request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
@@ -178,31 +188,32 @@ func (p *GroqProvider) Completion(ctx context.Context, req CompletionRequest) (s
 
 	resp, err := p.httpClient.Do(httpReq)
 	if err != nil {
-		return "", fmt.Errorf("error sending request: %w", err)
+		return "", Usage{}, fmt.Errorf("error sending request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("error reading response: %w", err)
+		return "", Usage{}, fmt.Errorf("error reading response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return "", Usage{}, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
 	}
 
 	var groqResp groqResponse
 	if err := json.Unmarshal(body, &groqResp); err != nil {
-		return "", fmt.Errorf("error unmarshaling response: %w", err)
+		return "", Usage{}, fmt.Errorf("error unmarshaling response: %w", err)
 	}
 
 	if groqResp.Error != nil {
-		return "", fmt.Errorf("groq API error: %s", groqResp.Error.Message)
+		return "", Usage{}, fmt.Errorf("groq API error: %s", groqResp.Error.Message)
 	}
 
 	if len(groqResp.Choices) == 0 {
-		return "", fmt.Errorf("no completion choices returned from Groq")
+		return "", Usage{}, fmt.Errorf("no completion choices returned from Groq")
 	}
 
-	return groqResp.Choices[0].Message.Content, nil
+	usage := Usage{PromptTokens: groqResp.Usage.PromptTokens, CompletionTokens: groqResp.Usage.CompletionTokens}
+	return groqResp.Choices[0].Message.Content, usage, nil
 }
@@ -25,13 +25,14 @@ func NewOpenAIProvider(apiKey string) *OpenAIProvider {
 
 // TranscribeAudio implements audio transcription using OpenAI's Whisper model
 func (p *OpenAIProvider) TranscribeAudio(ctx context.Context, filename string, reader AudioReader) (string, error) {
-	logger.Debugf("Transcription model: %s", state.Get().Config.LLM.Transcription.Model)
+	transcriptionCfg := state.Get().GetConfig().LLM.Transcription
+	logger.Debugf("Transcription model: %s", transcriptionCfg.Model)
 	req := openai.AudioRequest{
 		Reader:   reader,
 		FilePath: filename,
 		Format:   openai.AudioResponseFormatText,
-		Model:    state.Get().Config.LLM.Transcription.Model,
-		Language: state.Get().Config.LLM.Transcription.Language,
+		Model:    transcriptionCfg.Model,
+		Language: transcriptionCfg.Language,
 	}
 	resp, err := p.client.CreateTranscription(ctx, req)
 	if err != nil {
@@ -43,6 +44,12 @@ func (p *OpenAIProvider) TranscribeAudio(ctx context.Context, filename string, r
 
 // Completion sends a completion request to OpenAI API
 func (p *OpenAIProvider) Completion(ctx context.Context, req CompletionRequest) (string, error) {
+	response, _, err := p.CompletionWithUsage(ctx, req)
+	return response, err
+}
+
+// CompletionWithUsage sends a completion request to OpenAI API and reports its token usage
+func (p *OpenAIProvider) CompletionWithUsage(ctx context.Context, req CompletionRequest) (string, Usage, error) {
 	logger.Debugf("Sending completion request with model: %s", req.Model)
 
 	if req.MaxTokens == 0 {
@@ -71,12 +78,13 @@ func (p *OpenAIProvider) Completion(ctx context.Context, req CompletionRequest)
 		},
 	)
 	if err != nil {
-		return "", fmt.Errorf("error creating completion with OpenAI: %w", err)
+		return "", Usage{}, fmt.Errorf("error creating completion with OpenAI: %w", err)
 	}
 
 	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no completion choices returned from OpenAI")
+		return "", Usage{}, fmt.Errorf("no completion choices returned from OpenAI")
 	}
 
-	return resp.Choices[0].Message.Content, nil
+	usage := Usage{PromptTokens: resp.Usage.PromptTokens, CompletionTokens: resp.Usage.CompletionTokens}
+	return resp.Choices[0].Message.Content, usage, nil
 }
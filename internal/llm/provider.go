@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/dooshek/voicify/internal/redact"
 	"github.com/dooshek/voicify/internal/state"
 	"github.com/dooshek/voicify/internal/types"
 )
@@ -34,9 +35,47 @@ type Provider interface {
 	Completion(ctx context.Context, req CompletionRequest) (string, error)
 }
 
-// NewProvider creates a new LLM provider based on the provider type
+// Usage is the number of tokens a completion used
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// UsageProvider is a Provider that reports the token usage of completions
+type UsageProvider interface {
+	CompletionWithUsage(ctx context.Context, req CompletionRequest) (string, Usage, error)
+}
+
+// CompleteWithUsage runs a completion and returns its token usage, which is
+// zero when the provider does not report it
+func CompleteWithUsage(ctx context.Context, provider Provider, req CompletionRequest) (string, Usage, error) {
+	if up, ok := provider.(UsageProvider); ok {
+		return up.CompletionWithUsage(ctx, req)
+	}
+	response, err := provider.Completion(ctx, req)
+	return response, Usage{}, err
+}
+
+// NewProvider creates a new LLM provider based on the provider type.
+// Completion requests are redacted unless redaction is disabled in the config.
 func NewProvider(providerType types.LLMProvider) (Provider, error) {
-	llmKeys := state.Get().Config.LLM.Keys
+	provider, err := newProvider(providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := redact.New(state.Get().GetConfig().Redaction)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redaction config: %w", err)
+	}
+	if redactor == nil {
+		return provider, nil
+	}
+	return &redactingProvider{Provider: provider, redactor: redactor}, nil
+}
+
+func newProvider(providerType types.LLMProvider) (Provider, error) {
+	llmKeys := state.Get().GetConfig().LLM.Keys
 
 	switch providerType {
 	case types.ProviderOpenAI:
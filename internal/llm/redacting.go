@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/redact"
+)
+
+// redactingProvider replaces personal data and secrets in completion requests
+// with placeholders and puts the original values back into the response.
+// Audio transcription is passed through.
+type redactingProvider struct {
+	Provider
+	redactor *redact.Redactor
+}
+
+// Completion redacts all messages with a shared mapping, so a value repeated
+// across messages keeps one placeholder, and restores the response
+func (p *redactingProvider) Completion(ctx context.Context, req CompletionRequest) (string, error) {
+	response, _, err := p.CompletionWithUsage(ctx, req)
+	return response, err
+}
+
+// CompletionWithUsage is Completion reporting the wrapped provider's token usage
+func (p *redactingProvider) CompletionWithUsage(ctx context.Context, req CompletionRequest) (string, Usage, error) {
+	mapping := redact.NewMapping()
+	messages := make([]ChatCompletionMessage, len(req.Messages))
+	for i, message := range req.Messages {
+		content, err := p.redactor.Redact(message.Content, mapping)
+		if err != nil {
+			return "", Usage{}, err
+		}
+		messages[i] = ChatCompletionMessage{Role: message.Role, Content: content}
+	}
+	req.Messages = messages
+
+	if mapping.Len() > 0 {
+		logger.Debugf("LLM: Redacted %d values before completion", mapping.Len())
+	}
+
+	response, usage, err := CompleteWithUsage(ctx, p.Provider, req)
+	if err != nil {
+		return "", usage, err
+	}
+	return mapping.Restore(response), usage, nil
+}
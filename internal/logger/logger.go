@@ -24,6 +24,8 @@ var (
 	output       io.Writer = os.Stdout
 	logFile      *os.File
 	logger       zerolog.Logger
+	// redactor masks sensitive data in log lines; nil logs verbatim
+	redactor func(string) string
 )
 
 func (l Level) String() string {
@@ -73,9 +75,32 @@ func CloseLogFile() {
 	}
 }
 
+// SetRedactor installs a function that masks sensitive data in every log line
+func SetRedactor(redact func(string) string) {
+	redactor = redact
+	initLogger()
+}
+
+// redactingWriter passes formatted log lines through the redactor
+type redactingWriter struct {
+	out io.Writer
+}
+
+func (w redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.out, redactor(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
 func initLogger() {
+	out := output
+	if redactor != nil {
+		out = redactingWriter{out: output}
+	}
+
 	consoleWriter := zerolog.ConsoleWriter{
-		Out:        output,
+		Out:        out,
 		TimeFormat: "15:04:05",
 		NoColor:    logFile != nil, // Disable colors when writing to file
 	}
@@ -37,7 +37,7 @@ func (a *pluginAdapter) GetActions(transcription string) []types.PluginAction {
 	typesActions := make([]types.PluginAction, len(apiActions))
 
 	for i, apiAction := range apiActions {
-		typesActions[i] = &actionAdapter{apiAction: apiAction}
+		typesActions[i] = newActionAdapter(apiAction)
 	}
 
 	return typesActions
@@ -48,11 +48,60 @@ type actionAdapter struct {
 	apiAction pluginapi.PluginAction
 }
 
+// contextActionAdapter adapts actions implementing pluginapi.ContextAction.
+// Other actions do not implement types.ContextAction, so the router runs
+// them with Execute and stops waiting when the context is done.
+type contextActionAdapter struct {
+	*actionAdapter
+	contextAction pluginapi.ContextAction
+}
+
+func newActionAdapter(apiAction pluginapi.PluginAction) types.PluginAction {
+	adapter := &actionAdapter{apiAction: apiAction}
+	if contextAction, ok := apiAction.(pluginapi.ContextAction); ok {
+		return &contextActionAdapter{actionAdapter: adapter, contextAction: contextAction}
+	}
+	return adapter
+}
+
+// ExecuteContext calls the ExecuteContext method on the pluginapi action
+func (a *contextActionAdapter) ExecuteContext(ec *types.ExecutionContext, transcription string) (*types.ActionResult, error) {
+	result, err := a.contextAction.ExecuteContext(toAPIContext(ec), transcription)
+	return toTypesResult(result), err
+}
+
 // Execute calls the Execute method on the pluginapi action
 func (a *actionAdapter) Execute(transcription string) error {
 	return a.apiAction.Execute(transcription)
 }
 
+// ExecuteWithArgs passes the arguments to actions implementing
+// pluginapi.ArgsAction; string-only actions get the transcription
+func (a *actionAdapter) ExecuteWithArgs(transcription string, args types.Args) error {
+	if argsAction, ok := a.apiAction.(pluginapi.ArgsAction); ok {
+		return argsAction.ExecuteWithArgs(transcription, toAPIArgs(args))
+	}
+	return a.apiAction.Execute(transcription)
+}
+
+// ExecuteWithOutput returns the output of actions implementing
+// pluginapi.OutputAction; other actions produce no output
+func (a *actionAdapter) ExecuteWithOutput(transcription string) (string, error) {
+	if outputAction, ok := a.apiAction.(pluginapi.OutputAction); ok {
+		return outputAction.ExecuteWithOutput(transcription)
+	}
+	return "", a.apiAction.Execute(transcription)
+}
+
+// Handles asks actions implementing pluginapi.MatchAction whether they would
+// handle the transcription; for other actions it is not known
+func (a *actionAdapter) Handles(transcription string) (bool, bool) {
+	if matchAction, ok := a.apiAction.(pluginapi.MatchAction); ok {
+		return matchAction.Handles(transcription), true
+	}
+	return false, false
+}
+
 // GetMetadata adapts the pluginapi.ActionMetadata to types.ActionMetadata
 func (a *actionAdapter) GetMetadata() types.ActionMetadata {
 	apiMetadata := a.apiAction.GetMetadata()
@@ -62,5 +111,79 @@ func (a *actionAdapter) GetMetadata() types.ActionMetadata {
 		LLMRouterPrompt:   apiMetadata.LLMRouterPrompt,
 		SkipDefaultAction: apiMetadata.SkipDefaultAction,
 		Priority:          apiMetadata.Priority,
+		Parameters:        toTypesSchema(apiMetadata.Parameters),
+	}
+}
+
+// toTypesSchema converts a pluginapi.ParameterSchema to a types.ParameterSchema
+func toTypesSchema(schema *pluginapi.ParameterSchema) *types.ParameterSchema {
+	if schema == nil {
+		return nil
+	}
+	converted := &types.ParameterSchema{
+		Type:        schema.Type,
+		Description: schema.Description,
+		Required:    schema.Required,
+		Enum:        schema.Enum,
+		Items:       toTypesSchema(schema.Items),
+	}
+	if schema.Properties != nil {
+		converted.Properties = make(map[string]*types.ParameterSchema, len(schema.Properties))
+		for name, property := range schema.Properties {
+			converted.Properties[name] = toTypesSchema(property)
+		}
+	}
+	return converted
+}
+
+// toAPIArgs converts validated arguments, including nested objects, to pluginapi.Args
+func toAPIArgs(args types.Args) pluginapi.Args {
+	if args == nil {
+		return nil
+	}
+	converted := make(pluginapi.Args, len(args))
+	for name, value := range args {
+		converted[name] = toAPIArgValue(value)
+	}
+	return converted
+}
+
+func toAPIArgValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case types.Args:
+		return toAPIArgs(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = toAPIArgValue(item)
+		}
+		return items
+	}
+	return value
+}
+
+// toAPIContext converts the router's execution context for pluginapi actions
+func toAPIContext(ec *types.ExecutionContext) *pluginapi.ExecutionContext {
+	return &pluginapi.ExecutionContext{
+		Context:       ec.Context,
+		RequestID:     ec.RequestID,
+		RecordingMode: string(ec.RecordingMode),
+		Language:      ec.Language,
+		WindowTitle:   ec.WindowTitle,
+		WindowApp:     ec.WindowApp,
+		Args:          toAPIArgs(ec.Args),
+	}
+}
+
+// toTypesResult converts the result of a pluginapi action; nil stays nil
+func toTypesResult(result *pluginapi.ActionResult) *types.ActionResult {
+	if result == nil {
+		return nil
+	}
+	return &types.ActionResult{
+		Message:  result.Message,
+		URL:      result.URL,
+		Severity: types.Severity(result.Severity),
+		Text:     result.Text,
 	}
 }
@@ -0,0 +1,306 @@
+// Package code turns dictated code commands into source text: identifier
+// casing ("camel case user id" -> userId), a spoken symbol vocabulary
+// ("open paren", "equals equals", "arrow") and auto-paired brackets and quotes.
+package code
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// itemKind is the kind of a parsed piece of the transcription
+type itemKind int
+
+const (
+	wordItem itemKind = iota
+	casingItem
+	symbolItem
+)
+
+// item is a word or a recognized command
+type item struct {
+	kind   itemKind
+	text   string
+	casing casing
+	symbol symbol
+	// endsSentence is set on words followed by sentence punctuation
+	endsSentence bool
+}
+
+// phrase is a compiled vocabulary entry
+type phrase struct {
+	words []string
+	item  item
+}
+
+// sentenceEnd are the punctuation marks a casing command does not reach past
+const sentenceEnd = ".!?;:…"
+
+// token is a lowercase word with its byte span in the source text
+type token struct {
+	word  string
+	start int
+	end   int
+}
+
+var phrases = compilePhrases()
+
+// IsCodeWindow reports whether the focused app is an editor or terminal.
+// classes are substrings of the app name; empty uses the built-in list.
+func IsCodeWindow(app string, classes []string) bool {
+	if app == "" {
+		return false
+	}
+	if len(classes) == 0 {
+		classes = defaultWindowClasses
+	}
+	app = strings.ToLower(app)
+	for _, class := range classes {
+		if class != "" && strings.Contains(app, strings.ToLower(class)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Transform converts code commands in text. Prose without a strong command
+// ("camel case", "open paren", "equals equals", ...) is returned unchanged
+// with ok set to false.
+func Transform(text string) (string, bool) {
+	items := parse(text)
+	if !hasStrongCommand(items) {
+		return text, false
+	}
+	return render(items), true
+}
+
+// parse splits text into words and commands, longest phrases first
+func parse(text string) []item {
+	tokens := tokenize(text)
+	var items []item
+	for i := 0; i < len(tokens); {
+		if p := matchPhrase(tokens, i); p != nil {
+			items = append(items, p.item)
+			i += len(p.words)
+			continue
+		}
+		next := len(text)
+		if i+1 < len(tokens) {
+			next = tokens[i+1].start
+		}
+		items = append(items, item{
+			kind:         wordItem,
+			text:         text[tokens[i].start:tokens[i].end],
+			endsSentence: strings.ContainsAny(text[tokens[i].end:next], sentenceEnd),
+		})
+		i++
+	}
+	return items
+}
+
+// hasStrongCommand reports whether the items switch code mode on. Casing
+// commands only count at the start or right after a symbol, so prose that
+// mentions "camel case" is left alone.
+func hasStrongCommand(items []item) bool {
+	for i, it := range items {
+		if it.kind == symbolItem && it.symbol.strong {
+			return true
+		}
+		if it.kind == casingItem && (i == 0 || items[i-1].kind == symbolItem) {
+			return true
+		}
+	}
+	return false
+}
+
+// render writes items as code, auto-closing brackets and quotes left open
+func render(items []item) string {
+	w := &writer{}
+	for i := 0; i < len(items); i++ {
+		it := items[i]
+		switch it.kind {
+		case wordItem:
+			w.write(it.text, false, false)
+		case casingItem:
+			// The identifier ends at the next command or sentence end
+			var words []string
+			for i+1 < len(items) && items[i+1].kind == wordItem {
+				i++
+				words = append(words, items[i].text)
+				if items[i].endsSentence {
+					break
+				}
+			}
+			if len(words) > 0 {
+				w.write(applyCasing(words, it.casing), false, false)
+			}
+		case symbolItem:
+			w.writeSymbol(it.symbol)
+		}
+	}
+	w.closeAll()
+	return w.b.String()
+}
+
+// writer assembles code with symbol-aware spacing
+type writer struct {
+	b        strings.Builder
+	glueNext bool
+	// open holds the closers of brackets and quotes that are still open
+	open []string
+}
+
+// write appends piece, adding a space unless either side is glued
+func (w *writer) write(piece string, glueLeft, glueRight bool) {
+	out := w.b.String()
+	if out != "" && !glueLeft && !w.glueNext && !strings.HasSuffix(out, "\n") {
+		w.b.WriteString(" ")
+	}
+	w.b.WriteString(piece)
+	w.glueNext = glueRight
+}
+
+func (w *writer) writeSymbol(sym symbol) {
+	switch sym.spacing {
+	case spaced:
+		w.write(sym.text, false, false)
+	case glued:
+		w.write(sym.text, true, true)
+	case trailing:
+		w.write(sym.text, true, false)
+	case lineBreak:
+		w.write(sym.text, true, true)
+	case opening:
+		if sym.text == "{" {
+			// Blocks keep spaces inside: "if x { return }"
+			w.write(sym.text, false, false)
+		} else {
+			// "foo(" but "x = ("
+			w.write(sym.text, w.afterOperand(), true)
+		}
+		w.open = append(w.open, pairs[sym.text])
+	case closing:
+		if n := len(w.open); n > 0 && w.open[n-1] == sym.text {
+			w.open = w.open[:n-1]
+		}
+		w.write(sym.text, sym.text != "}", false)
+	case quoting:
+		if n := len(w.open); n > 0 && w.open[n-1] == sym.text {
+			w.open = w.open[:n-1]
+			w.write(sym.text, true, false)
+			return
+		}
+		w.write(sym.text, false, true)
+		w.open = append(w.open, sym.text)
+	}
+}
+
+// afterOperand reports whether the output ends with an identifier or closer
+func (w *writer) afterOperand() bool {
+	out := w.b.String()
+	if out == "" || w.glueNext {
+		return false
+	}
+	last := []rune(out)[len([]rune(out))-1]
+	return unicode.IsLetter(last) || unicode.IsDigit(last) || strings.ContainsRune("_)]}", last)
+}
+
+// closeAll closes brackets and quotes that were opened but never closed
+func (w *writer) closeAll() {
+	for i := len(w.open) - 1; i >= 0; i-- {
+		w.write(w.open[i], w.open[i] != "}", false)
+	}
+	w.open = nil
+}
+
+// applyCasing joins words into an identifier
+func applyCasing(words []string, style casing) string {
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+
+	switch style {
+	case snakeCase:
+		return strings.Join(words, "_")
+	case kebabCase:
+		return strings.Join(words, "-")
+	case constantCase:
+		return strings.ToUpper(strings.Join(words, "_"))
+	case pascalCase:
+		return joinCapitalized(words, 0)
+	}
+	return joinCapitalized(words, 1)
+}
+
+// joinCapitalized concatenates words, capitalizing those from index from on
+func joinCapitalized(words []string, from int) string {
+	var b strings.Builder
+	for i, word := range words {
+		if i >= from && word != "" {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			word = string(runes)
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// matchPhrase returns the longest vocabulary phrase at i
+func matchPhrase(tokens []token, i int) *phrase {
+	for k := range phrases {
+		p := &phrases[k]
+		if i+len(p.words) > len(tokens) {
+			continue
+		}
+		matched := true
+		for j, word := range p.words {
+			if tokens[i+j].word != word {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return p
+		}
+	}
+	return nil
+}
+
+// compilePhrases builds the phrase list from the vocabulary, longest first
+func compilePhrases() []phrase {
+	var compiled []phrase
+	for spoken, style := range casingCommands {
+		compiled = append(compiled, phrase{words: strings.Fields(spoken), item: item{kind: casingItem, casing: style}})
+	}
+	for spoken, sym := range symbols {
+		compiled = append(compiled, phrase{words: strings.Fields(spoken), item: item{kind: symbolItem, symbol: sym}})
+	}
+	sort.SliceStable(compiled, func(i, j int) bool {
+		if len(compiled[i].words) != len(compiled[j].words) {
+			return len(compiled[i].words) > len(compiled[j].words)
+		}
+		return strings.Join(compiled[i].words, " ") < strings.Join(compiled[j].words, " ")
+	})
+	return compiled
+}
+
+// tokenize splits text into lowercase words with their byte spans
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\''
+		if isWordRune && start < 0 {
+			start = i
+		} else if !isWordRune && start >= 0 {
+			tokens = append(tokens, token{word: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{word: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
@@ -0,0 +1,137 @@
+package code
+
+// casing is an identifier style selected by a spoken command
+type casing int
+
+const (
+	camelCase casing = iota
+	pascalCase
+	snakeCase
+	kebabCase
+	constantCase
+)
+
+// casingCommands map spoken casing commands to identifier styles
+var casingCommands = map[string]casing{
+	"camel case":           camelCase,
+	"camelcase":            camelCase,
+	"pascal case":          pascalCase,
+	"snake case":           snakeCase,
+	"kebab case":           kebabCase,
+	"constant case":        constantCase,
+	"screaming snake case": constantCase,
+	"upper snake case":     constantCase,
+}
+
+// spacing controls how a symbol joins the surrounding code
+type spacing int
+
+const (
+	spaced    spacing = iota // " = "
+	glued                    // "." and "_" join both sides
+	trailing                 // "," and ";" join left, space right
+	opening                  // "(" joins both sides, opens a pair
+	closing                  // ")" joins left, closes a pair
+	quoting                  // '"' opens or closes a string literal
+	lineBreak                // "\n"
+)
+
+// symbol is a spoken symbol and how it is written
+type symbol struct {
+	text    string
+	spacing spacing
+	// strong symbols switch code mode on; weak ones ("dot", "plus") are only
+	// read as symbols when a strong command was spoken in the same utterance
+	strong bool
+}
+
+// symbols is the spoken symbol vocabulary (English and Polish)
+var symbols = map[string]symbol{
+	"open paren":        {"(", opening, true},
+	"open parenthesis":  {"(", opening, true},
+	"left paren":        {"(", opening, true},
+	"close paren":       {")", closing, true},
+	"close parenthesis": {")", closing, true},
+	"right paren":       {")", closing, true},
+	"open bracket":      {"[", opening, true},
+	"close bracket":     {"]", closing, true},
+	"open brace":        {"{", opening, true},
+	"open curly":        {"{", opening, true},
+	"close brace":       {"}", closing, true},
+	"close curly":       {"}", closing, true},
+	"double quote":      {`"`, quoting, true},
+	"single quote":      {"'", quoting, true},
+	"backtick":          {"`", quoting, true},
+	"quote":             {`"`, quoting, false},
+
+	"triple equals":        {"===", spaced, true},
+	"equals equals equals": {"===", spaced, true},
+	"equals equals":        {"==", spaced, true},
+	"double equals":        {"==", spaced, true},
+	"not equals":           {"!=", spaced, true},
+	"plus equals":          {"+=", spaced, true},
+	"minus equals":         {"-=", spaced, true},
+	"colon equals":         {":=", spaced, true},
+	"greater or equal":     {">=", spaced, true},
+	"less or equal":        {"<=", spaced, true},
+	"greater than":         {">", spaced, false},
+	"less than":            {"<", spaced, false},
+	"logical and":          {"&&", spaced, true},
+	"logical or":           {"||", spaced, true},
+	"fat arrow":            {"=>", spaced, true},
+	"arrow":                {"->", spaced, false},
+	"equals":               {"=", spaced, false},
+	"plus":                 {"+", spaced, false},
+	"minus":                {"-", spaced, false},
+	"times":                {"*", spaced, false},
+	"star":                 {"*", spaced, false},
+	"slash":                {"/", spaced, false},
+	"pipe":                 {"|", spaced, false},
+	"ampersand":            {"&", spaced, false},
+
+	"double colon": {"::", glued, true},
+	"dot":          {".", glued, false},
+	"underscore":   {"_", glued, false},
+	"dash":         {"-", glued, false},
+	"bang":         {"!", glued, false},
+	"at sign":      {"@", glued, true},
+	"hash":         {"#", glued, false},
+	"dollar sign":  {"$", glued, true},
+	"comma":        {",", trailing, false},
+	"semicolon":    {";", trailing, false},
+	"colon":        {":", trailing, false},
+	"new line":     {"\n", lineBreak, false},
+
+	"otwórz nawias":             {"(", opening, true},
+	"zamknij nawias":            {")", closing, true},
+	"otwórz nawias kwadratowy":  {"[", opening, true},
+	"zamknij nawias kwadratowy": {"]", closing, true},
+	"otwórz klamrę":             {"{", opening, true},
+	"zamknij klamrę":            {"}", closing, true},
+	"cudzysłów":                 {`"`, quoting, false},
+	"apostrof":                  {"'", quoting, false},
+	"równa się równa się":       {"==", spaced, true},
+	"różne od":                  {"!=", spaced, true},
+	"równa się":                 {"=", spaced, false},
+	"strzałka":                  {"->", spaced, false},
+	"kropka":                    {".", glued, false},
+	"podkreślnik":               {"_", glued, false},
+	"przecinek":                 {",", trailing, false},
+	"średnik":                   {";", trailing, false},
+	"dwukropek":                 {":", trailing, false},
+	"nowa linia":                {"\n", lineBreak, false},
+}
+
+// pairs maps opening brackets to their closing counterparts
+var pairs = map[string]string{
+	"(": ")",
+	"[": "]",
+	"{": "}",
+}
+
+// defaultWindowClasses are focused app names treated as editors and terminals
+var defaultWindowClasses = []string{
+	"code", "codium", "cursor", "zed", "jetbrains", "idea", "pycharm", "goland",
+	"sublime", "gedit", "kate", "emacs", "vim",
+	"terminal", "kitty", "alacritty", "wezterm", "konsole", "foot", "ghostty", "tilix",
+}
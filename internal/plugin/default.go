@@ -2,6 +2,7 @@ package plugin
 
 import (
 	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/rewrite"
 	"github.com/dooshek/voicify/pkg/pluginapi"
 )
 
@@ -41,7 +42,7 @@ func (a *DefaultAction) Execute(transcription string) error {
 	logger.Debug("Default plugin: Executing default action - copy to clipboard and paste")
 
 	// Use RequestPaste which will use DBus if available, or fallback to clipboard
-	return RequestPaste(transcription)
+	return RequestPaste(rewrite.ForPaste(transcription))
 }
 
 // GetMetadata returns metadata about the action
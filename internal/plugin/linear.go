@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -58,8 +59,29 @@ func (p *LinearPlugin) GetActions(transcription string) []pluginapi.PluginAction
 	}
 }
 
-// Execute executes the Linear action
+// Execute executes the Linear action without a deadline
 func (a *LinearAction) Execute(transcription string) error {
+	_, err := a.ExecuteContext(&pluginapi.ExecutionContext{Context: context.Background()}, transcription)
+	return err
+}
+
+// ExecuteWithOutput executes the Linear action without a deadline and returns
+// the URL of the ticket it created or updated, or else what the agent said
+func (a *LinearAction) ExecuteWithOutput(transcription string) (string, error) {
+	result, err := a.ExecuteContext(&pluginapi.ExecutionContext{Context: context.Background()}, transcription)
+	if result == nil {
+		return "", err
+	}
+	if result.URL != "" {
+		return result.URL, err
+	}
+	return result.Message, err
+}
+
+// ExecuteContext runs the agentic loop for the transcription until it waits
+// for the user's response or completes. Its MCP and LLM calls stop when
+// ec.Context is done, which also ends the conversation.
+func (a *LinearAction) ExecuteContext(ec *pluginapi.ExecutionContext, transcription string) (*pluginapi.ActionResult, error) {
 	logger.Debugf("Linear plugin: Executing action for transcription: %s", transcription)
 	logger.Info("🔧 Linear plugin: Akcja została uruchomiona - plugin Linear jest aktywny")
 
@@ -74,7 +96,7 @@ func (a *LinearAction) Execute(transcription string) error {
 			a.plugin.mu.Unlock()
 			logger.Errorf("Failed to initialize agentic loop: %v", err)
 			logger.Info("⚠️  Linear MCP client may not be initialized yet. Try again in a moment.")
-			return fmt.Errorf("failed to initialize agentic loop: %w", err)
+			return nil, fmt.Errorf("failed to initialize agentic loop: %w", err)
 		}
 		a.plugin.agenticLoop = agenticLoop
 		logger.Debug("Agentic loop created successfully")
@@ -89,29 +111,39 @@ func (a *LinearAction) Execute(transcription string) error {
 	case linear.StateIdle:
 		// Start new agentic loop
 		logger.Debug("Starting new agentic loop")
-		if err := agenticLoop.Start(transcription); err != nil {
-			return err
+		fields := linear.TicketFields{
+			Title:    ec.Args.String("title"),
+			Priority: ec.Args.String("priority"),
+			Assignee: ec.Args.String("assignee"),
 		}
-		// Don't return here - let the loop continue running
-		logger.Debug("Agentic loop started, continuing to listen for responses")
-		return nil
+		outcome, err := agenticLoop.Start(ec.Context, transcription, fields)
+		return outcomeResult(outcome), err
 	case linear.StateWaitingResponse:
 		// Process user response
 		logger.Debug("Processing user response")
-		if err := agenticLoop.ProcessResponse(transcription); err != nil {
-			return err
-		}
-		// Don't return here - let the loop continue running
-		logger.Debug("User response processed, continuing agentic loop")
-		return nil
+		outcome, err := agenticLoop.ProcessResponse(ec.Context, transcription)
+		return outcomeResult(outcome), err
 	case linear.StateAnswering:
 		// Agent is providing answer, ignore new transcriptions
 		logger.Debug("Agent is providing answer, ignoring transcription")
-		return nil
+		return nil, nil
 	default:
 		logger.Warnf("Agentic loop is in state %s, ignoring transcription", currentState)
+		return nil, nil
+	}
+}
+
+// outcomeResult reports what the agent said and the issue it created or
+// updated; an empty outcome reports nothing
+func outcomeResult(outcome linear.Outcome) *pluginapi.ActionResult {
+	if outcome.Message == "" && outcome.URL == "" {
 		return nil
 	}
+	return &pluginapi.ActionResult{
+		Message:  outcome.Message,
+		URL:      outcome.URL,
+		Severity: pluginapi.SeverityInfo,
+	}
 }
 
 // GetMetadata returns metadata about the action
@@ -155,9 +187,31 @@ Kluczowe: musi być wyraźne POLECENIE AKCJI, nie tylko wzmianka o Linear.`
 		Description:     "wykonanie akcji w Linear - tworzenie i edycja ticketów",
 		Priority:        2,
 		LLMRouterPrompt: &prompt,
+		Parameters:      linearParameters,
 	}
 }
 
+// linearParameters are the ticket details the router extracts for new tickets.
+// All are optional, as searches and edits have none of them.
+var linearParameters = &pluginapi.ParameterSchema{
+	Type: "object",
+	Properties: map[string]*pluginapi.ParameterSchema{
+		"title": {
+			Type:        "string",
+			Description: "tytuł nowego ticketu, bez słów polecenia",
+		},
+		"priority": {
+			Type:        "string",
+			Description: "priorytet ticketu, tylko gdy użytkownik go podał",
+			Enum:        []interface{}{"urgent", "high", "medium", "low", "none"},
+		},
+		"assignee": {
+			Type:        "string",
+			Description: "osoba, do której przypisać ticket; \"me\" gdy użytkownik mówi o sobie",
+		},
+	},
+}
+
 // NewLinearPlugin creates a new instance of the Linear plugin
 func NewLinearPlugin() pluginapi.VoicifyPlugin {
 	return &LinearPlugin{}
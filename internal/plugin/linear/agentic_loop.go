@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -28,6 +29,45 @@ const (
 	StateError           AgenticLoopState = "error"
 )
 
+// issueURLRegex finds Linear issue URLs in tool results
+var issueURLRegex = regexp.MustCompile(`https://linear\.app/[^\s"'\\)]+/issue/[^\s"'\\)]+`)
+
+// Outcome is what a run of the loop tells the user
+type Outcome struct {
+	// Message is the question, answer or summary the agent spoke
+	Message string
+	// URL is the last issue the agent created or updated in this conversation
+	URL string
+}
+
+// linearPriorities are the Linear priority numbers of the spoken priority names
+var linearPriorities = map[string]int{"urgent": 1, "high": 2, "medium": 3, "low": 4, "none": 0}
+
+// TicketFields are ticket details the router already extracted from the
+// utterance. The agent uses them as they are instead of inferring them again.
+type TicketFields struct {
+	Title    string
+	Priority string // urgent, high, medium, low or none
+	Assignee string // a name, or "me"
+}
+
+// describe lists the fields for the agent prompts, or returns "" when none is set
+func (f TicketFields) describe() string {
+	var lines []string
+	if f.Title != "" {
+		lines = append(lines, fmt.Sprintf("- tytuł: %q", f.Title))
+	}
+	if priority, ok := linearPriorities[f.Priority]; ok {
+		lines = append(lines, fmt.Sprintf("- priorytet: %s (priority = %d w Linear)", f.Priority, priority))
+	}
+	if f.Assignee != "" {
+		lines = append(lines, fmt.Sprintf("- przypisz do: %s", f.Assignee))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\nDane ticketu podane przez użytkownika (użyj dokładnie tych wartości):\n" + strings.Join(lines, "\n")
+}
 
 // AgenticLoop manages the conversational flow using MCP tools
 type AgenticLoop struct {
@@ -40,26 +80,26 @@ type AgenticLoop struct {
 	userIntent         string
 	currentQuestion    string
 	questionSpoken     bool
-	ctx                context.Context
-	cancel             context.CancelFunc
+	// err is why the loop entered StateError
+	err error
+	// cancel stops the run in progress
+	cancel context.CancelFunc
+	// issueURL is the last issue created or updated in this conversation
+	issueURL string
 }
 
 // NewAgenticLoop creates a new agentic loop instance
 func NewAgenticLoop() (*AgenticLoop, error) {
-	ctx, cancel := context.WithCancel(context.Background())
-
 	// Get TTS manager from global state
 	ttsManagerInterface := state.Get().GetTTSManager()
 	ttsManager, ok := ttsManagerInterface.(*tts.Manager)
 	if !ok {
-		cancel()
 		return nil, fmt.Errorf("TTS manager not available or wrong type")
 	}
 
 	// Get or create global MCP client for Linear
 	mcpClient, err := getOrCreateGlobalMCPClient()
 	if err != nil {
-		cancel()
 		return nil, fmt.Errorf("failed to get Linear MCP client: %w", err)
 	}
 
@@ -68,8 +108,6 @@ func NewAgenticLoop() (*AgenticLoop, error) {
 		ttsManager:          ttsManager,
 		mcpClient:           mcpClient,
 		conversationHistory: make([]string, 0),
-		ctx:                 ctx,
-		cancel:              cancel,
 	}
 
 	logger.Debug("Agentic loop initialized")
@@ -98,26 +136,26 @@ func getOrCreateGlobalMCPClient() (*LinearMCPClient, error) {
 	return client, nil
 }
 
-// Start begins the agentic loop process
-func (al *AgenticLoop) Start(initialTranscription string) error {
+// Start begins the agentic loop process and runs it until it waits for the
+// user's response, completes or ctx is done
+func (al *AgenticLoop) Start(ctx context.Context, initialTranscription string, fields TicketFields) (Outcome, error) {
 	al.mu.Lock()
-	defer al.mu.Unlock()
-
 	if al.state != StateIdle {
-		return fmt.Errorf("agentic loop is already running")
+		al.mu.Unlock()
+		return Outcome{}, fmt.Errorf("agentic loop is already running")
 	}
 
 	logger.Infof("Starting agentic loop with transcription: %s", initialTranscription)
 
 	// Store user intent
-	al.userIntent = initialTranscription
+	al.userIntent = initialTranscription + fields.describe()
 	al.conversationHistory = append(al.conversationHistory, fmt.Sprintf("User: %s", initialTranscription))
 
 	// Start the analysis process
 	al.state = StateAnalyzing
-	go al.runLoop()
+	al.mu.Unlock()
 
-	return nil
+	return al.run(ctx)
 }
 
 // Stop stops the agentic loop
@@ -130,8 +168,10 @@ func (al *AgenticLoop) Stop() {
 	}
 
 	logger.Debug("Stopping agentic loop")
-	al.cancel()
-	al.state = StateIdle
+	if al.cancel != nil {
+		al.cancel()
+	}
+	al.resetLocked()
 }
 
 // GetState returns the current state of the loop
@@ -141,13 +181,14 @@ func (al *AgenticLoop) GetState() AgenticLoopState {
 	return al.state
 }
 
-// ProcessResponse processes user's voice response
-func (al *AgenticLoop) ProcessResponse(response string) error {
+// ProcessResponse processes user's voice response and runs the loop until it
+// waits for the next response, completes or ctx is done
+func (al *AgenticLoop) ProcessResponse(ctx context.Context, response string) (Outcome, error) {
 	al.mu.Lock()
-	defer al.mu.Unlock()
-
 	if al.state != StateWaitingResponse {
-		return fmt.Errorf("not waiting for response, current state: %s", al.state)
+		state := al.state
+		al.mu.Unlock()
+		return Outcome{}, fmt.Errorf("not waiting for response, current state: %s", state)
 	}
 
 	logger.Debugf("Processing response: %s", response)
@@ -157,49 +198,94 @@ func (al *AgenticLoop) ProcessResponse(response string) error {
 
 	// Move to analysis state
 	al.state = StateAnalyzing
-	// Don't start new goroutine - the existing runLoop will continue
+	al.mu.Unlock()
 
-	return nil
+	return al.run(ctx)
 }
 
-// runLoop runs the main loop logic
-func (al *AgenticLoop) runLoop() {
+// run advances the loop until it waits for the user's response, completes or
+// fails. The conversation ends when ctx is done.
+func (al *AgenticLoop) run(ctx context.Context) (Outcome, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	al.mu.Lock()
+	al.cancel = cancel
+	al.mu.Unlock()
+
 	for {
-		select {
-		case <-al.ctx.Done():
+		if err := ctx.Err(); err != nil {
 			logger.Debug("Agentic loop context cancelled")
-			return
-		default:
+			al.reset()
+			return Outcome{}, err
 		}
 
-		al.mu.Lock()
-		currentState := al.state
-		al.mu.Unlock()
-
-		switch currentState {
+		switch al.GetState() {
 		case StateAnalyzing:
-			al.handleAnalyzing()
+			al.handleAnalyzing(ctx)
 		case StateAskingQuestion:
-			al.handleAskingQuestion()
+			al.handleAskingQuestion(ctx)
 		case StateAnswering:
-			al.handleAnswering()
+			al.handleAnswering(ctx)
 		case StateExecutingTools:
-			al.handleExecutingTools()
+			al.handleExecutingTools(ctx)
+		case StateWaitingResponse:
+			al.mu.RLock()
+			outcome := Outcome{Message: al.currentQuestion, URL: al.issueURL}
+			al.mu.RUnlock()
+			return outcome, nil
 		case StateCompleted:
 			logger.Debug("Agentic loop completed successfully")
-			al.handleCompletion()
-			return
+			summary := al.handleCompletion(ctx)
+			al.mu.RLock()
+			outcome := Outcome{Message: summary, URL: al.issueURL}
+			al.mu.RUnlock()
+			al.reset()
+			return outcome, nil
 		case StateError:
 			logger.Debug("Agentic loop ended with error")
-			return
+			al.mu.Lock()
+			err := al.err
+			al.resetLocked()
+			al.mu.Unlock()
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return Outcome{}, ctxErr
+			}
+			return Outcome{}, err
 		default:
-			time.Sleep(100 * time.Millisecond)
+			return Outcome{}, nil
 		}
 	}
 }
 
+// fail moves the loop to StateError
+func (al *AgenticLoop) fail(err error) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.err = err
+	al.state = StateError
+}
+
+// reset ends the conversation so the next transcription starts a new one
+func (al *AgenticLoop) reset() {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.resetLocked()
+}
+
+// resetLocked is reset for callers holding al.mu
+func (al *AgenticLoop) resetLocked() {
+	al.state = StateIdle
+	al.err = nil
+	al.cancel = nil
+	al.userIntent = ""
+	al.currentQuestion = ""
+	al.questionSpoken = false
+	al.issueURL = ""
+	al.conversationHistory = make([]string, 0)
+}
+
 // handleAnalyzing handles the analysis state using LLM
-func (al *AgenticLoop) handleAnalyzing() {
+func (al *AgenticLoop) handleAnalyzing(ctx context.Context) {
 	logger.Debug("Analyzing conversation and determining next action")
 
 	al.mu.Lock()
@@ -209,22 +295,18 @@ func (al *AgenticLoop) handleAnalyzing() {
 	al.mu.Unlock()
 
 	// Get available MCP tools
-	tools, err := al.mcpClient.GetAvailableTools()
+	tools, err := al.mcpClient.GetAvailableTools(ctx)
 	if err != nil {
 		logger.Errorf("Failed to get available tools: %v", err)
-		al.mu.Lock()
-		al.state = StateError
-		al.mu.Unlock()
+		al.fail(fmt.Errorf("failed to get Linear tools: %w", err))
 		return
 	}
 
 	// Use LLM to analyze conversation and determine next action
-	nextAction, question, err := al.analyzeWithLLM(conversationHistory, userIntent, tools)
+	nextAction, question, err := al.analyzeWithLLM(ctx, conversationHistory, userIntent, tools)
 	if err != nil {
 		logger.Errorf("LLM analysis failed: %v", err)
-		al.mu.Lock()
-		al.state = StateError
-		al.mu.Unlock()
+		al.fail(fmt.Errorf("LLM analysis failed: %w", err))
 		return
 	}
 
@@ -261,14 +343,12 @@ func (al *AgenticLoop) handleAnalyzing() {
 		al.mu.Unlock()
 	default:
 		logger.Warnf("Unknown action: %s", nextAction)
-		al.mu.Lock()
-		al.state = StateError
-		al.mu.Unlock()
+		al.fail(fmt.Errorf("unknown agent action %q", nextAction))
 	}
 }
 
 // handleAskingQuestion handles asking questions via TTS
-func (al *AgenticLoop) handleAskingQuestion() {
+func (al *AgenticLoop) handleAskingQuestion(ctx context.Context) {
 	al.mu.Lock()
 	question := al.currentQuestion
 	questionSpoken := al.questionSpoken
@@ -276,9 +356,7 @@ func (al *AgenticLoop) handleAskingQuestion() {
 
 	if question == "" {
 		logger.Warn("No question to ask")
-		al.mu.Lock()
-		al.state = StateError
-		al.mu.Unlock()
+		al.fail(fmt.Errorf("agent asked an empty question"))
 		return
 	}
 
@@ -291,15 +369,13 @@ func (al *AgenticLoop) handleAskingQuestion() {
 	logger.Debugf("Speaking question: %s", question)
 
 	// Use TTS to ask the question
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	speakCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	question = fmt.Sprintf("Powiedz dokładnie tylko to co jest w cudzysłowie: \"%s\"", question)
-	if err := al.ttsManager.Speak(ctx, question); err != nil {
+	if err := al.ttsManager.Speak(speakCtx, question); err != nil {
 		logger.Errorf("Failed to speak question: %v", err)
-		al.mu.Lock()
-		al.state = StateError
-		al.mu.Unlock()
+		al.fail(fmt.Errorf("failed to speak question: %w", err))
 		return
 	}
 
@@ -314,7 +390,7 @@ func (al *AgenticLoop) handleAskingQuestion() {
 }
 
 // handleAnswering handles providing answers to user via TTS
-func (al *AgenticLoop) handleAnswering() {
+func (al *AgenticLoop) handleAnswering(ctx context.Context) {
 	al.mu.Lock()
 	answer := al.currentQuestion // currentQuestion now contains the answer
 	answerSpoken := al.questionSpoken
@@ -322,9 +398,7 @@ func (al *AgenticLoop) handleAnswering() {
 
 	if answer == "" {
 		logger.Warn("No answer to provide")
-		al.mu.Lock()
-		al.state = StateError
-		al.mu.Unlock()
+		al.fail(fmt.Errorf("agent gave an empty answer"))
 		return
 	}
 
@@ -340,15 +414,13 @@ func (al *AgenticLoop) handleAnswering() {
 	logger.Debugf("Speaking answer: %s", answer)
 
 	// Use TTS to provide the answer
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	speakCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	answer = fmt.Sprintf("Powiedz dokładnie tylko to co jest w cudzysłowie: \"%s\"", answer)
-	if err := al.ttsManager.Speak(ctx, answer); err != nil {
+	if err := al.ttsManager.Speak(speakCtx, answer); err != nil {
 		logger.Errorf("Failed to speak answer: %v", err)
-		al.mu.Lock()
-		al.state = StateError
-		al.mu.Unlock()
+		al.fail(fmt.Errorf("failed to speak answer: %w", err))
 		return
 	}
 
@@ -363,7 +435,7 @@ func (al *AgenticLoop) handleAnswering() {
 }
 
 // handleExecutingTools handles executing MCP tools
-func (al *AgenticLoop) handleExecutingTools() {
+func (al *AgenticLoop) handleExecutingTools(ctx context.Context) {
 	logger.Debug("Executing MCP tools")
 
 	al.mu.Lock()
@@ -373,12 +445,10 @@ func (al *AgenticLoop) handleExecutingTools() {
 	al.mu.Unlock()
 
 	// Use LLM to determine which tools to execute and with what parameters
-	toolsToExecute, err := al.determineToolsToExecute(conversationHistory, userIntent)
+	toolsToExecute, err := al.determineToolsToExecute(ctx, conversationHistory, userIntent)
 	if err != nil {
 		logger.Errorf("Failed to determine tools to execute: %v", err)
-		al.mu.Lock()
-		al.state = StateError
-		al.mu.Unlock()
+		al.fail(fmt.Errorf("failed to determine tools to execute: %w", err))
 		return
 	}
 
@@ -387,8 +457,11 @@ func (al *AgenticLoop) handleExecutingTools() {
 	for _, toolCall := range toolsToExecute {
 		logger.Debugf("Executing tool: %s with params: %+v", toolCall.Name, toolCall.Parameters)
 
-		result, err := al.mcpClient.ExecuteTool(toolCall.Name, toolCall.Parameters)
+		result, err := al.mcpClient.ExecuteTool(ctx, toolCall.Name, toolCall.Parameters)
 		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
 			logger.Errorf("Failed to execute tool %s", err, toolCall.Name)
 			// Add error to conversation history so LLM can learn from it
 			errorMsg := fmt.Sprintf("OSTATNI BŁĄD wykonania narzędzia %s: %s", toolCall.Name, err.Error())
@@ -398,6 +471,11 @@ func (al *AgenticLoop) handleExecutingTools() {
 
 		results = append(results, fmt.Sprintf("Tool %s result: %s", toolCall.Name, result))
 		logger.Debugf("Tool %s executed successfully", toolCall.Name)
+		if url := modifiedIssueURL(toolCall.Name, result); url != "" {
+			al.mu.Lock()
+			al.issueURL = url
+			al.mu.Unlock()
+		}
 	}
 
 	// Add results to conversation history
@@ -412,8 +490,18 @@ func (al *AgenticLoop) handleExecutingTools() {
 	// The current runLoop will continue in the next iteration
 }
 
-// handleCompletion handles the completion state and provides voice summary
-func (al *AgenticLoop) handleCompletion() {
+// modifiedIssueURL returns the URL of the issue a create or update tool
+// returned; results of searches are ignored
+func modifiedIssueURL(tool, result string) string {
+	tool = strings.ToLower(tool)
+	if !strings.Contains(tool, "create") && !strings.Contains(tool, "update") && !strings.Contains(tool, "save") {
+		return ""
+	}
+	return issueURLRegex.FindString(result)
+}
+
+// handleCompletion handles the completion state, speaks a summary and returns it
+func (al *AgenticLoop) handleCompletion(ctx context.Context) string {
 	al.mu.Lock()
 	conversationHistory := make([]string, len(al.conversationHistory))
 	copy(conversationHistory, al.conversationHistory)
@@ -421,7 +509,7 @@ func (al *AgenticLoop) handleCompletion() {
 	al.mu.Unlock()
 
 	// Generate completion summary using LLM
-	summary, err := al.generateCompletionSummary(conversationHistory, userIntent)
+	summary, err := al.generateCompletionSummary(ctx, conversationHistory, userIntent)
 	if err != nil {
 		logger.Errorf("Failed to generate completion summary: %v", err)
 		summary = "Zadanie zostało zakończone."
@@ -430,18 +518,19 @@ func (al *AgenticLoop) handleCompletion() {
 	logger.Infof("Agentic loop completed: %s", summary)
 
 	// Speak the summary
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	speakCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	if err := al.ttsManager.Speak(ctx, summary); err != nil {
+	if err := al.ttsManager.Speak(speakCtx, summary); err != nil {
 		logger.Errorf("Failed to speak completion summary: %v", err)
 	}
 
 	logger.Debug("Completion summary spoken")
+	return summary
 }
 
 // generateCompletionSummary generates a summary of what was accomplished
-func (al *AgenticLoop) generateCompletionSummary(conversationHistory []string, userIntent string) (string, error) {
+func (al *AgenticLoop) generateCompletionSummary(ctx context.Context, conversationHistory []string, userIntent string) (string, error) {
 	conversation := strings.Join(conversationHistory, "\n")
 
 	prompt := fmt.Sprintf(`Przeanalizuj konwersację i napisz BARDZO KRÓTKĄ odpowiedź (maksymalnie 1 zdanie, 10-15 słów).
@@ -470,7 +559,7 @@ NIE pisz długich podsumowań. Odpowiedz tylko krótkim potwierdzeniem.`, conver
 		Temperature: 0.3,
 	}
 
-	response, err := llmProvider.Completion(context.Background(), req)
+	response, err := llmProvider.Completion(ctx, req)
 	if err != nil {
 		return "", fmt.Errorf("LLM completion failed: %w", err)
 	}
@@ -479,7 +568,7 @@ NIE pisz długich podsumowań. Odpowiedz tylko krótkim potwierdzeniem.`, conver
 }
 
 // analyzeWithLLM uses LLM to analyze conversation and determine next action
-func (al *AgenticLoop) analyzeWithLLM(conversationHistory []string, userIntent string, tools []MCPTool) (string, string, error) {
+func (al *AgenticLoop) analyzeWithLLM(ctx context.Context, conversationHistory []string, userIntent string, tools []MCPTool) (string, string, error) {
 	// Build prompt for LLM
 	prompt := al.buildAnalysisPrompt(conversationHistory, userIntent, tools)
 
@@ -495,7 +584,7 @@ func (al *AgenticLoop) analyzeWithLLM(conversationHistory []string, userIntent s
 		Temperature: 0.7,
 	}
 
-	response, err := llmProvider.Completion(context.Background(), req)
+	response, err := llmProvider.Completion(ctx, req)
 	if err != nil {
 		return "", "", fmt.Errorf("LLM completion failed: %w", err)
 	}
@@ -577,9 +666,9 @@ IMPORTANT: ZAWSZE odpowiadaj w formacie JSON beż zbędnych innych znaków na po
 }
 
 // determineToolsToExecute uses LLM to determine which tools to execute
-func (al *AgenticLoop) determineToolsToExecute(conversationHistory []string, userIntent string) ([]ToolCall, error) {
+func (al *AgenticLoop) determineToolsToExecute(ctx context.Context, conversationHistory []string, userIntent string) ([]ToolCall, error) {
 	// Get available tools
-	tools, err := al.mcpClient.GetAvailableTools()
+	tools, err := al.mcpClient.GetAvailableTools(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -599,7 +688,7 @@ func (al *AgenticLoop) determineToolsToExecute(conversationHistory []string, use
 		Temperature: 0.3, // Lower temperature for more consistent tool selection
 	}
 
-	response, err := llmProvider.Completion(context.Background(), req)
+	response, err := llmProvider.Completion(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("LLM completion failed: %w", err)
 	}
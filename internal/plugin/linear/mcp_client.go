@@ -2,6 +2,7 @@ package linear
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -222,7 +223,7 @@ func (c *LinearMCPClient) startProcess() error {
 }
 
 // GetAvailableTools retrieves all available MCP tools from Linear MCP server
-func (c *LinearMCPClient) GetAvailableTools() ([]MCPTool, error) {
+func (c *LinearMCPClient) GetAvailableTools(ctx context.Context) ([]MCPTool, error) {
 	// Return cached tools if available
 	if c.toolsCached {
 		logger.Debug("Returning cached MCP tools")
@@ -232,7 +233,7 @@ func (c *LinearMCPClient) GetAvailableTools() ([]MCPTool, error) {
 	logger.Debug("Getting available MCP tools from Linear via npx mcp-remote")
 
 	// Call npx mcp-remote to list tools
-	result, err := c.callMCPRemote("tools/list", nil)
+	result, err := c.callMCPRemote(ctx, "tools/list", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tools: %w", err)
 	}
@@ -269,7 +270,7 @@ func (c *LinearMCPClient) GetAvailableTools() ([]MCPTool, error) {
 }
 
 // ExecuteTool executes a specific MCP tool with given parameters
-func (c *LinearMCPClient) ExecuteTool(toolName string, parameters map[string]interface{}) (string, error) {
+func (c *LinearMCPClient) ExecuteTool(ctx context.Context, toolName string, parameters map[string]interface{}) (string, error) {
 	logger.Debugf("Executing MCP tool: %s with parameters: %+v", toolName, parameters)
 
 	// Prepare tool call parameters
@@ -279,7 +280,7 @@ func (c *LinearMCPClient) ExecuteTool(toolName string, parameters map[string]int
 	}
 
 	// Call npx mcp-remote to execute tool
-	result, err := c.callMCPRemote("tools/call", toolParams)
+	result, err := c.callMCPRemote(ctx, "tools/call", toolParams)
 	if err != nil {
 		return "", fmt.Errorf("failed to call tool: %w", err)
 	}
@@ -299,7 +300,7 @@ func (c *LinearMCPClient) ExecuteTool(toolName string, parameters map[string]int
 }
 
 // callMCPRemote calls npx mcp-remote with the given method and params via STDIO
-func (c *LinearMCPClient) callMCPRemote(method string, params interface{}) ([]byte, error) {
+func (c *LinearMCPClient) callMCPRemote(ctx context.Context, method string, params interface{}) ([]byte, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -314,8 +315,13 @@ func (c *LinearMCPClient) callMCPRemote(method string, params interface{}) ([]by
 			return nil, fmt.Errorf("failed to ensure process running: %w", err)
 		}
 
-		result, err := c.doMCPCall(method, params)
+		result, err := c.doMCPCall(ctx, method, params)
 		if err != nil {
+			if ctx.Err() != nil {
+				// The abandoned response would be read by the next call
+				c.forceRestart()
+				return nil, ctx.Err()
+			}
 			logger.Debugf("MCP call failed on attempt %d: %v", attempt+1, err)
 			if attempt == 0 {
 				// Force restart on first failure
@@ -355,7 +361,7 @@ func (c *LinearMCPClient) forceRestart() {
 }
 
 // doMCPCall performs the actual MCP call
-func (c *LinearMCPClient) doMCPCall(method string, params interface{}) ([]byte, error) {
+func (c *LinearMCPClient) doMCPCall(ctx context.Context, method string, params interface{}) ([]byte, error) {
 	// Prepare MCP request
 	request := MCPRequest{
 		JSONRPC: "2.0",
@@ -404,6 +410,8 @@ func (c *LinearMCPClient) doMCPCall(method string, params interface{}) ([]byte,
 		return response, nil
 	case err := <-errorCh:
 		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	case <-time.After(15 * time.Second): // Increased timeout
 		return nil, fmt.Errorf("mcp-remote response timeout")
 	}
@@ -8,6 +8,24 @@ func RegisterAllPlugins(manager *Manager) error {
 		return err
 	}
 
+	// Register Undo plugin
+	undoPlugin := NewPluginAdapter(NewUndoPlugin())
+	if err := manager.RegisterPlugin(undoPlugin); err != nil {
+		return err
+	}
+
+	// Register Spelling plugin
+	spellingPlugin := NewPluginAdapter(NewSpellingPlugin())
+	if err := manager.RegisterPlugin(spellingPlugin); err != nil {
+		return err
+	}
+
+	// Register Snippets plugin
+	snippetsPlugin := NewPluginAdapter(NewSnippetsPlugin())
+	if err := manager.RegisterPlugin(snippetsPlugin); err != nil {
+		return err
+	}
+
 	// Register VSCode plugin
 	vscodePlugin := NewPluginAdapter(NewVSCodePlugin())
 	if err := manager.RegisterPlugin(vscodePlugin); err != nil {
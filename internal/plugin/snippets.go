@@ -0,0 +1,268 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dooshek/voicify/internal/fileops"
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/pkg/pluginapi"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	snippetsFilename = "snippets.yaml"
+	// defaultSnippetSimilarity is the minimum similarity for a fuzzy match
+	defaultSnippetSimilarity = 0.85
+)
+
+// placeholderRegex matches {{name}} and {{name:format}} placeholders
+var placeholderRegex = regexp.MustCompile(`\{\{\s*(\w+)(?::([^}]*))?\s*\}\}`)
+
+// SnippetLibrary is the user snippet file (snippets.yaml in the config dir)
+//
+//	similarity: 0.85   # 1.0 disables fuzzy matching
+//	snippets:
+//	  - name: signature
+//	    triggers: ["insert my signature", "wstaw podpis"]
+//	    expansion: |
+//	      Best regards,
+//	      Jan
+//	  - name: standup
+//	    triggers: ["standup template"]
+//	    exact: true
+//	    expansion: "Standup {{date}}\nYesterday:\nToday:\nBlockers:"
+//
+// Placeholders: {{date}}, {{time}}, {{datetime}} (with an optional Go layout,
+// e.g. {{date:02.01.2006}}), {{clipboard}}, {{app}} and {{title}}.
+type SnippetLibrary struct {
+	Similarity float64   `yaml:"similarity"`
+	Snippets   []Snippet `yaml:"snippets"`
+}
+
+// Snippet maps spoken triggers to an expansion
+type Snippet struct {
+	Name      string   `yaml:"name"`
+	Triggers  []string `yaml:"triggers"`
+	Expansion string   `yaml:"expansion"`
+	Exact     bool     `yaml:"exact"` // disables fuzzy matching for this snippet
+}
+
+// SnippetsPlugin expands spoken triggers into saved text
+type SnippetsPlugin struct{}
+
+// SnippetsAction pastes the expansion of a matching snippet
+type SnippetsAction struct {
+	transcription string
+}
+
+// Initialize initializes the snippets plugin
+func (p *SnippetsPlugin) Initialize() error {
+	logger.Debug("Snippets plugin initialized")
+	return nil
+}
+
+// GetMetadata returns metadata about the plugin
+func (p *SnippetsPlugin) GetMetadata() pluginapi.PluginMetadata {
+	return pluginapi.PluginMetadata{
+		Name:        "snippets",
+		Version:     "1.0.0",
+		Description: "Plugin for expanding spoken triggers into saved snippets",
+		Author:      "Voicify Team",
+	}
+}
+
+// GetActions returns a list of actions provided by this plugin
+func (p *SnippetsPlugin) GetActions(transcription string) []pluginapi.PluginAction {
+	return []pluginapi.PluginAction{
+		&SnippetsAction{transcription: transcription},
+	}
+}
+
+// Execute pastes the expansion of the snippet matching the transcription.
+// The library is read on every call so edits apply without a restart.
+func (a *SnippetsAction) Execute(transcription string) error {
+	library, err := LoadSnippets()
+	if err != nil {
+		logger.Warnf("Snippets plugin: %v", err)
+		return pluginapi.ErrActionSkipped
+	}
+
+	snippet := library.Match(transcription)
+	if snippet == nil {
+		logger.Debugf("Snippets plugin: No snippet matches transcription: %s", transcription)
+		return pluginapi.ErrActionSkipped
+	}
+
+	logger.Debugf("Snippets plugin: Expanding snippet %q", snippet.Name)
+	return RequestPaste(expandPlaceholders(snippet.Expansion))
+}
+
+// Handles reports whether a snippet matches the transcription
+func (a *SnippetsAction) Handles(transcription string) bool {
+	library, err := LoadSnippets()
+	return err == nil && library.Match(transcription) != nil
+}
+
+// GetMetadata returns metadata about the action
+func (a *SnippetsAction) GetMetadata() pluginapi.ActionMetadata {
+	return pluginapi.ActionMetadata{
+		Name:              "snippets",
+		Description:       "wstawianie zapisanych fragmentów tekstu",
+		SkipDefaultAction: true, // wklejamy rozwinięcie zamiast wypowiedzianych słów
+		Priority:          10,
+	}
+}
+
+// NewSnippetsPlugin creates a new instance of the snippets plugin
+func NewSnippetsPlugin() pluginapi.VoicifyPlugin {
+	return &SnippetsPlugin{}
+}
+
+// LoadSnippets reads the snippet library from the config directory.
+// A missing file yields an empty library.
+func LoadSnippets() (*SnippetLibrary, error) {
+	fileOps, err := fileops.NewDefaultFileOps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize file operations: %w", err)
+	}
+
+	library := &SnippetLibrary{}
+	data, err := fileOps.LoadConfig(snippetsFilename)
+	if err != nil {
+		if errors.Is(err, fileops.ErrConfigNotFound) {
+			return library, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", snippetsFilename, err)
+	}
+
+	if err := yaml.Unmarshal(data, library); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", snippetsFilename, err)
+	}
+	return library, nil
+}
+
+// Match returns the snippet whose trigger matches the transcription.
+// Exact matches win over fuzzy ones; among fuzzy matches the most similar wins.
+func (l *SnippetLibrary) Match(transcription string) *Snippet {
+	spoken := normalizeSpoken(transcription)
+	if spoken == "" {
+		return nil
+	}
+
+	threshold := l.Similarity
+	if threshold <= 0 {
+		threshold = defaultSnippetSimilarity
+	}
+
+	var best *Snippet
+	bestScore := 0.0
+	for i := range l.Snippets {
+		snippet := &l.Snippets[i]
+		for _, trigger := range snippet.Triggers {
+			trigger = normalizeSpoken(trigger)
+			if trigger == "" {
+				continue
+			}
+			if trigger == spoken {
+				return snippet
+			}
+			if snippet.Exact {
+				continue
+			}
+			if score := similarity(spoken, trigger); score >= threshold && score > bestScore {
+				best = snippet
+				bestScore = score
+			}
+		}
+	}
+	return best
+}
+
+// expandPlaceholders substitutes placeholders in a snippet expansion
+func expandPlaceholders(expansion string) string {
+	now := time.Now()
+	return placeholderRegex.ReplaceAllStringFunc(expansion, func(match string) string {
+		groups := placeholderRegex.FindStringSubmatch(match)
+		name, layout := strings.ToLower(groups[1]), strings.TrimSpace(groups[2])
+
+		switch name {
+		case "date":
+			return now.Format(layoutOr(layout, "2006-01-02"))
+		case "time":
+			return now.Format(layoutOr(layout, "15:04"))
+		case "datetime":
+			return now.Format(layoutOr(layout, "2006-01-02 15:04"))
+		case "clipboard":
+			text, err := ReadClipboard()
+			if err != nil {
+				logger.Warnf("Snippets plugin: Failed to read clipboard: %v", err)
+				return ""
+			}
+			return text
+		case "app":
+			_, app := state.Get().GetFocusedWindow()
+			return app
+		case "title":
+			title, _ := state.Get().GetFocusedWindow()
+			return title
+		}
+
+		logger.Warnf("Snippets plugin: Unknown placeholder %s", match)
+		return match
+	})
+}
+
+func layoutOr(layout, fallback string) string {
+	if layout == "" {
+		return fallback
+	}
+	return layout
+}
+
+// normalizeSpoken lowercases text and reduces it to words separated by single spaces
+func normalizeSpoken(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// similarity returns 1 minus the normalized Levenshtein distance of a and b
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,74 @@
+package plugin
+
+import (
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/spelling"
+	"github.com/dooshek/voicify/pkg/pluginapi"
+)
+
+// SpellingPlugin pastes spelled-out names, IDs and passwords character by character
+type SpellingPlugin struct{}
+
+// SpellingAction assembles a spelled utterance and pastes it
+type SpellingAction struct {
+	transcription string
+}
+
+// Initialize initializes the spelling plugin
+func (p *SpellingPlugin) Initialize() error {
+	logger.Debug("Spelling plugin initialized")
+	return nil
+}
+
+// GetMetadata returns metadata about the plugin
+func (p *SpellingPlugin) GetMetadata() pluginapi.PluginMetadata {
+	return pluginapi.PluginMetadata{
+		Name:        "spelling",
+		Version:     "1.0.0",
+		Description: "Plugin for spelling text letter by letter",
+		Author:      "Voicify Team",
+	}
+}
+
+// GetActions returns a list of actions provided by this plugin
+func (p *SpellingPlugin) GetActions(transcription string) []pluginapi.PluginAction {
+	return []pluginapi.PluginAction{
+		&SpellingAction{transcription: transcription},
+	}
+}
+
+// Execute pastes the spelled text for "spell ..." commands and for every
+// utterance while spelling mode is on. Mode phrases only switch the mode.
+func (a *SpellingAction) Execute(transcription string) error {
+	spelled, handled := spelling.Interpret(transcription)
+	if !handled {
+		return pluginapi.ErrActionSkipped
+	}
+	if spelled == "" {
+		logger.Debug("Spelling plugin: Nothing to paste")
+		return nil
+	}
+
+	logger.Debugf("Spelling plugin: %s -> %s", transcription, spelled)
+	return RequestPaste(spelled)
+}
+
+// Handles reports whether the utterance is spelled or switches spelling mode
+func (a *SpellingAction) Handles(transcription string) bool {
+	return spelling.Requested(transcription)
+}
+
+// GetMetadata returns metadata about the action
+func (a *SpellingAction) GetMetadata() pluginapi.ActionMetadata {
+	return pluginapi.ActionMetadata{
+		Name:              "spelling",
+		Description:       "literowanie tekstu znak po znaku",
+		SkipDefaultAction: true, // wklejamy przeliterowany tekst zamiast wypowiedzianych słów
+		Priority:          20,
+	}
+}
+
+// NewSpellingPlugin creates a new instance of the spelling plugin
+func NewSpellingPlugin() pluginapi.VoicifyPlugin {
+	return &SpellingPlugin{}
+}
@@ -0,0 +1,128 @@
+package plugin
+
+import (
+	"github.com/dooshek/voicify/internal/history"
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/pkg/pluginapi"
+)
+
+// undoPhrases and redoPhrases are whole utterances that undo or redo the last insertion
+var (
+	undoPhrases = map[string]bool{
+		"undo": true, "undo that": true, "scratch that": true, "delete that": true,
+		"cofnij": true, "cofnij to": true, "skreśl to": true, "wymaż to": true, "usuń to": true,
+	}
+	redoPhrases = map[string]bool{
+		"redo": true, "redo that": true,
+		"ponów": true, "ponów to": true, "przywróć to": true,
+	}
+)
+
+// UndoPlugin removes and restores text inserted by voicify
+type UndoPlugin struct{}
+
+// UndoAction handles "undo that" and "redo that"
+type UndoAction struct {
+	transcription string
+}
+
+// Initialize initializes the undo plugin
+func (p *UndoPlugin) Initialize() error {
+	logger.Debug("Undo plugin initialized")
+	return nil
+}
+
+// GetMetadata returns metadata about the plugin
+func (p *UndoPlugin) GetMetadata() pluginapi.PluginMetadata {
+	return pluginapi.PluginMetadata{
+		Name:        "undo",
+		Version:     "1.0.0",
+		Description: "Plugin for undoing and redoing inserted text",
+		Author:      "Voicify Team",
+	}
+}
+
+// GetActions returns a list of actions provided by this plugin
+func (p *UndoPlugin) GetActions(transcription string) []pluginapi.PluginAction {
+	return []pluginapi.PluginAction{
+		&UndoAction{transcription: transcription},
+	}
+}
+
+// Execute undoes or redoes the last insertion when the whole utterance is an
+// undo or redo command. Failures are only logged, so the command itself is
+// never pasted by a later action.
+func (a *UndoAction) Execute(transcription string) error {
+	command := normalizeSpoken(transcription)
+
+	var err error
+	switch {
+	case undoPhrases[command]:
+		err = UndoLast()
+	case redoPhrases[command]:
+		err = RedoLast()
+	default:
+		return pluginapi.ErrActionSkipped
+	}
+
+	if err != nil {
+		logger.Warnf("Undo plugin: %q failed: %v", command, err)
+	}
+	return nil
+}
+
+// Handles reports whether the whole utterance is an undo or redo command
+func (a *UndoAction) Handles(transcription string) bool {
+	command := normalizeSpoken(transcription)
+	return undoPhrases[command] || redoPhrases[command]
+}
+
+// GetMetadata returns metadata about the action
+func (a *UndoAction) GetMetadata() pluginapi.ActionMetadata {
+	return pluginapi.ActionMetadata{
+		Name:              "undo",
+		Description:       "cofanie i ponawianie wstawionego tekstu",
+		SkipDefaultAction: true, // polecenie nie jest wklejane
+		Priority:          30,
+	}
+}
+
+// NewUndoPlugin creates a new instance of the undo plugin
+func NewUndoPlugin() pluginapi.VoicifyPlugin {
+	return &UndoPlugin{}
+}
+
+// UndoLast deletes the last insertion from the focused window, provided focus
+// has not moved since it was inserted. It stays on the undo stack when the
+// deletion cannot be requested.
+func UndoLast() error {
+	insertion, err := history.PeekUndo()
+	if err != nil {
+		return err
+	}
+
+	chars := insertion.Chars()
+	logger.Debugf("plugin: Undoing insertion of %d characters: %s", chars, insertion.Text)
+	if err := pluginapi.RequestDelete(chars); err != nil {
+		return err
+	}
+	_, err = history.Undo()
+	return err
+}
+
+// RedoLast inserts the last undone text again. It stays on the redo stack
+// when the text could only be copied to the clipboard.
+func RedoLast() error {
+	insertion, err := history.PeekRedo()
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("plugin: Redoing insertion: %s", insertion.Text)
+	if err := pluginapi.RequestPaste(insertion.Text); err != nil {
+		logger.Debugf("plugin: RequestPaste via DBus failed (%v), falling back to clipboard", err)
+		return PasteWithReturn(insertion.Text)
+	}
+	_, err = history.Redo()
+	return err
+}
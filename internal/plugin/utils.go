@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/dooshek/voicify/internal/clipboard"
+	"github.com/dooshek/voicify/internal/history"
 	"github.com/dooshek/voicify/internal/logger"
 	"github.com/dooshek/voicify/internal/windowdetect"
 	"github.com/dooshek/voicify/pkg/pluginapi"
@@ -25,6 +26,11 @@ func CopyToClipboard(text string) error {
 	return clipboard.CopyToClipboard(text)
 }
 
+// ReadClipboard returns the current clipboard text
+func ReadClipboard() (string, error) {
+	return clipboard.ReadClipboard()
+}
+
 // PasteWithReturn pastes text and adds a newline
 func PasteWithReturn(text string) error {
 	logger.Debugf("plugin: PasteWithReturn: %s", text)
@@ -33,7 +39,8 @@ func PasteWithReturn(text string) error {
 
 // RequestPaste requests the extension to paste text via DBus (daemon mode only)
 // This is the preferred method for pasting in post-transcription mode
-// Falls back to clipboard.PasteWithReturn if DBus is not available
+// Falls back to clipboard.PasteWithReturn if DBus is not available. The
+// fallback only copies the text, so it is not recorded for undo.
 func RequestPaste(text string) error {
 	logger.Debugf("plugin: RequestPaste: %s", text)
 
@@ -46,6 +53,7 @@ func RequestPaste(text string) error {
 	}
 
 	logger.Debugf("plugin: RequestPaste via DBus successful")
+	history.Record(text)
 	return nil
 }
 
@@ -4,7 +4,10 @@ import (
 	"strings"
 
 	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/plugin/code"
+	"github.com/dooshek/voicify/internal/rewrite"
 	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/internal/types"
 	"github.com/dooshek/voicify/pkg/pluginapi"
 )
 
@@ -43,17 +46,44 @@ func (p *VSCodePlugin) GetActions(transcription string) []pluginapi.PluginAction
 func (a *VSCodeAction) Execute(transcription string) error {
 	logger.Debugf("VSCode plugin: Checking if VSCode should execute action for transcription: %s", transcription)
 
+	if !a.Handles(transcription) {
+		logger.Debug("VSCode plugin: No editor or terminal is focused, skipping action")
+		return pluginapi.ErrActionSkipped
+	}
+
+	converted, ok := "", false
+	if !codeDictationConfig().Disabled {
+		converted, ok = code.Transform(transcription)
+	}
+	if ok {
+		logger.Debugf("VSCode plugin: Code dictation: %s -> %s", transcription, converted)
+		transcription = converted
+	} else {
+		// Dictated code is pasted as converted; prose gets the window's rewrite profile
+		transcription = rewrite.ForPaste(transcription)
+	}
+
+	logger.Debug("VSCode plugin: Editor is focused, executing action")
+	return RequestPaste(transcription)
+}
+
+// Handles reports whether an editor or terminal is focused
+func (a *VSCodeAction) Handles(transcription string) bool {
 	// Use cached focused window from state instead of xdotool
 	title, app := state.Get().GetFocusedWindow()
 	logger.Debugf("VSCode plugin: Cached focused window - title: %s, app: %s", title, app)
 
-	if !strings.Contains(app, "code") && !strings.Contains(title, "VSC") {
-		logger.Debug("VSCode plugin: VSCode is not focused, skipping action")
-		return nil
-	}
+	isVSCode := strings.Contains(app, "code") || strings.Contains(title, "VSC")
+	return isVSCode || code.IsCodeWindow(app, codeDictationConfig().WindowClasses)
+}
 
-	logger.Debug("VSCode plugin: VSCode is focused, executing action")
-	return RequestPaste(transcription)
+// codeDictationConfig returns the code dictation settings, empty without a config
+func codeDictationConfig() types.CodeDictationConfig {
+	cfg := state.Get().GetConfig()
+	if cfg == nil {
+		return types.CodeDictationConfig{}
+	}
+	return cfg.CodeDictation
 }
 
 // GetMetadata returns metadata about the action
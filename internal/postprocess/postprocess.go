@@ -0,0 +1,166 @@
+package postprocess
+
+import (
+	"strings"
+
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/plugin/code"
+	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/internal/types"
+)
+
+// Stage names accepted in the post_process configuration
+const (
+	StageWhitespace        = "whitespace"
+	StageCapitalization    = "capitalization"
+	StageSpokenPunctuation = "spoken_punctuation"
+	StageDictionary        = "dictionary"
+	StageFillers           = "fillers"
+	StageITN               = "itn"
+)
+
+// DefaultStages is the chain used when no stages are configured. Only
+// hesitation sounds are removed; the other stages rewrite what the model
+// returned and are opt-in.
+var DefaultStages = []string{
+	StageFillers,
+}
+
+// Stage transforms transcribed text before it is routed
+type Stage interface {
+	Name() string
+	Process(text string) string
+}
+
+// Pipeline runs stages in order
+type Pipeline struct {
+	stages []Stage
+}
+
+// NewPipeline builds the stage chain for the given configuration and focused app
+func NewPipeline(cfg types.PostProcessConfig, app string) *Pipeline {
+	p := &Pipeline{}
+	for _, name := range stageNames(cfg, app) {
+		stage := newStage(name, cfg)
+		if stage == nil {
+			logger.Warnf("Unknown post-processing stage: %s", name)
+			continue
+		}
+		p.stages = append(p.stages, stage)
+	}
+	return p
+}
+
+// Process runs text through every stage
+func (p *Pipeline) Process(text string) string {
+	for _, stage := range p.stages {
+		processed := stage.Process(text)
+		if processed != text {
+			logger.Debugf("Post-processing [%s]: %q -> %q", stage.Name(), text, processed)
+		}
+		text = processed
+	}
+	return text
+}
+
+// Stages returns the names of the stages in the chain
+func (p *Pipeline) Stages() []string {
+	names := make([]string, 0, len(p.stages))
+	for _, stage := range p.stages {
+		names = append(names, stage.Name())
+	}
+	return names
+}
+
+// Apply post-processes text using the global configuration and the app of the focused window
+func Apply(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return text
+	}
+
+	appState := state.Get()
+	cfg := appState.GetConfig()
+	if cfg == nil {
+		return text
+	}
+
+	// Editors and terminals get the text as transcribed: spacing and
+	// capitalization fixes would break code, commands and paths
+	_, app := appState.GetFocusedWindow()
+	if code.IsCodeWindow(app, cfg.CodeDictation.WindowClasses) {
+		logger.Debugf("Skipping post-processing in code window: %s", app)
+		return text
+	}
+	return NewPipeline(cfg.PostProcess, app).Process(text)
+}
+
+// stageNames resolves the ordered stage list, applying overrides matching app
+func stageNames(cfg types.PostProcessConfig, app string) []string {
+	names := cfg.Stages
+	if len(names) == 0 {
+		names = DefaultStages
+	}
+	names = append([]string(nil), names...)
+
+	if cfg.ITN && !containsName(names, StageITN) {
+		names = append(names, StageITN)
+	}
+
+	app = strings.ToLower(app)
+	for _, override := range cfg.Apps {
+		if override.Match == "" || app == "" || !strings.Contains(app, strings.ToLower(override.Match)) {
+			continue
+		}
+		names = removeNames(names, override.Disable)
+		for _, name := range override.Enable {
+			if !containsName(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	// Normalization runs first: later stages would read "dwa przecinek pięć"
+	// as punctuation and drop the fillers between number words
+	if containsName(names, StageITN) {
+		names = append([]string{StageITN}, removeNames(names, []string{StageITN})...)
+	}
+	return names
+}
+
+// newStage creates a built-in stage by name
+func newStage(name string, cfg types.PostProcessConfig) Stage {
+	switch name {
+	case StageWhitespace:
+		return whitespaceStage{}
+	case StageCapitalization:
+		return capitalizationStage{}
+	case StageSpokenPunctuation:
+		return newSpokenPunctuationStage()
+	case StageDictionary:
+		return newDictionaryStage(cfg.Replacements)
+	case StageFillers:
+		return newFillerStage(cfg.FillerWords)
+	case StageITN:
+		return newITNStage()
+	}
+	return nil
+}
+
+func removeNames(names []string, remove []string) []string {
+	var kept []string
+	for _, name := range names {
+		if !containsName(remove, name) {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
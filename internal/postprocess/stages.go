@@ -0,0 +1,326 @@
+package postprocess
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dooshek/voicify/internal/itn"
+	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/internal/types"
+)
+
+var (
+	horizontalSpaceRegex  = regexp.MustCompile(`[ \t]+`)
+	spaceBeforePunctRegex = regexp.MustCompile(` +([,.;:!?…])`)
+	missingSpaceRegex     = regexp.MustCompile(`([,;!?])(\p{L})`)
+	commaBeforeEndRegex   = regexp.MustCompile(`,+\s*([.!?…])`)
+	repeatedCommaRegex    = regexp.MustCompile(`,{2,}`)
+	blankLinesRegex       = regexp.MustCompile(`\n{3,}`)
+)
+
+// whitespaceStage collapses spaces and fixes spacing around punctuation
+type whitespaceStage struct{}
+
+func (whitespaceStage) Name() string { return StageWhitespace }
+
+func (whitespaceStage) Process(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = horizontalSpaceRegex.ReplaceAllString(line, " ")
+		line = spaceBeforePunctRegex.ReplaceAllString(line, "$1")
+		line = commaBeforeEndRegex.ReplaceAllString(line, "$1")
+		line = repeatedCommaRegex.ReplaceAllString(line, ",")
+		line = addMissingSpaces(line)
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = strings.Join(lines, "\n")
+
+	text = blankLinesRegex.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// addMissingSpaces separates words glued by a comma or sentence mark ("tak,ale").
+// Words that look like URLs, paths or code ("example.com/?a=b,c", "f(a,b)")
+// are left as they are.
+func addMissingSpaces(line string) string {
+	words := strings.Split(line, " ")
+	for i, word := range words {
+		if looksLikeCode(word) {
+			continue
+		}
+		words[i] = missingSpaceRegex.ReplaceAllString(word, "$1 $2")
+	}
+	return strings.Join(words, " ")
+}
+
+// looksLikeCode reports whether word contains symbols or an inner dot that
+// do not appear in dictated prose
+func looksLikeCode(word string) bool {
+	return strings.ContainsAny(word, codeSymbols) || strings.Contains(strings.TrimRight(word, "."), ".")
+}
+
+// codeSymbols are characters that mark a word as a URL, path or code
+const codeSymbols = `/\:=@#$%&*()[]{}<>_|~^"'` + "`"
+
+// capitalizationStage uppercases the first letter of the text, of every
+// sentence and of every line. Existing capitals are never lowered.
+type capitalizationStage struct{}
+
+func (capitalizationStage) Name() string { return StageCapitalization }
+
+func (capitalizationStage) Process(text string) string {
+	var b strings.Builder
+	capitalizeNext := true
+	afterTerminator := false
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			capitalizeNext = true
+			afterTerminator = false
+		case unicode.IsSpace(r):
+			if afterTerminator {
+				capitalizeNext = true
+			}
+		case r == '.' || r == '!' || r == '?' || r == '…':
+			afterTerminator = true
+		case unicode.IsLetter(r):
+			if capitalizeNext {
+				r = unicode.ToUpper(r)
+			}
+			capitalizeNext = false
+			afterTerminator = false
+		case unicode.IsDigit(r):
+			capitalizeNext = false
+			afterTerminator = false
+		default:
+			// Quotes and brackets keep the pending capitalization
+			afterTerminator = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// itnStage writes spoken numbers, dates, times, currencies and units in written form
+type itnStage struct {
+	language string
+}
+
+// newITNStage uses the transcription language; without one every supported language is tried
+func newITNStage() *itnStage {
+	var language string
+	if cfg := state.Get().GetConfig(); cfg != nil && itn.Supported(cfg.LLM.Transcription.Language) {
+		language = cfg.LLM.Transcription.Language
+	}
+	return &itnStage{language: language}
+}
+
+func (s *itnStage) Name() string { return StageITN }
+
+func (s *itnStage) Process(text string) string {
+	return itn.Normalize(text, s.language)
+}
+
+// phraseReplacement maps a spoken phrase to its replacement
+type phraseReplacement struct {
+	words       []string
+	replacement string
+}
+
+// spokenPunctuation maps spoken punctuation (English and Polish) to symbols
+var spokenPunctuation = map[string]string{
+	"comma":              ",",
+	"period":             ".",
+	"full stop":          ".",
+	"question mark":      "?",
+	"exclamation mark":   "!",
+	"exclamation point":  "!",
+	"colon":              ":",
+	"semicolon":          ";",
+	"ellipsis":           "…",
+	"przecinek":          ",",
+	"kropka":             ".",
+	"znak zapytania":     "?",
+	"pytajnik":           "?",
+	"wykrzyknik":         "!",
+	"znak wykrzyknienia": "!",
+	"dwukropek":          ":",
+	"średnik":            ";",
+	"wielokropek":        "…",
+}
+
+// spokenPunctuationStage turns spoken punctuation words into symbols
+type spokenPunctuationStage struct {
+	phrases []phraseReplacement
+}
+
+func newSpokenPunctuationStage() *spokenPunctuationStage {
+	s := &spokenPunctuationStage{}
+	for spoken, symbol := range spokenPunctuation {
+		s.phrases = append(s.phrases, phraseReplacement{words: splitWords(spoken), replacement: symbol})
+	}
+	sortLongestFirst(s.phrases)
+	return s
+}
+
+func (s *spokenPunctuationStage) Name() string { return StageSpokenPunctuation }
+
+func (s *spokenPunctuationStage) Process(text string) string {
+	tokens := tokenize(text)
+	var spans []span
+	for i := 0; i < len(tokens); i++ {
+		p := matchPhrase(s.phrases, tokens, i)
+		if p == nil {
+			continue
+		}
+
+		// Attach the symbol to the preceding word, absorbing punctuation the
+		// transcription model may have added around the spoken word
+		start := tokens[i].start
+		for start > 0 && (text[start-1] == ' ' || text[start-1] == ',') {
+			start--
+		}
+		end := tokens[i+len(p.words)-1].end
+		if end < len(text) && (text[end] == ',' || text[end] == '.') {
+			end++
+		}
+
+		spans = append(spans, span{start: start, end: end, replacement: p.replacement})
+		i += len(p.words) - 1
+	}
+	return applySpans(text, spans)
+}
+
+// dictionaryStage applies the user find/replace dictionary
+type dictionaryStage struct {
+	phrases []phraseReplacement
+}
+
+func newDictionaryStage(replacements []types.Replacement) *dictionaryStage {
+	s := &dictionaryStage{}
+	for _, r := range replacements {
+		words := splitWords(r.Find)
+		if len(words) == 0 {
+			continue
+		}
+		s.phrases = append(s.phrases, phraseReplacement{words: words, replacement: r.Replace})
+	}
+	sortLongestFirst(s.phrases)
+	return s
+}
+
+func (s *dictionaryStage) Name() string { return StageDictionary }
+
+func (s *dictionaryStage) Process(text string) string {
+	if len(s.phrases) == 0 {
+		return text
+	}
+
+	tokens := tokenize(text)
+	var spans []span
+	for i := 0; i < len(tokens); i++ {
+		p := matchPhrase(s.phrases, tokens, i)
+		if p == nil {
+			continue
+		}
+		spans = append(spans, span{
+			start:       tokens[i].start,
+			end:         tokens[i+len(p.words)-1].end,
+			replacement: p.replacement,
+		})
+		i += len(p.words) - 1
+	}
+	return applySpans(text, spans)
+}
+
+// defaultFillerWords are hesitation sounds in English and Polish
+var defaultFillerWords = []string{
+	"um", "umm", "uh", "uhm", "erm", "hm", "hmm", "mhm",
+	"yyy", "yy", "eee", "ee", "eem", "yhm",
+}
+
+// fillerStage removes hesitation sounds together with their surrounding commas
+type fillerStage struct {
+	words map[string]bool
+}
+
+func newFillerStage(extra []string) *fillerStage {
+	s := &fillerStage{words: make(map[string]bool)}
+	for _, word := range append(append([]string(nil), defaultFillerWords...), extra...) {
+		s.words[strings.ToLower(strings.TrimSpace(word))] = true
+	}
+	return s
+}
+
+func (s *fillerStage) Name() string { return StageFillers }
+
+func (s *fillerStage) Process(text string) string {
+	var spans []span
+	for _, t := range tokenize(text) {
+		if !s.isFiller(t.word) {
+			continue
+		}
+
+		end := t.end
+		for end < len(text) && (text[end] == ',' || strings.HasPrefix(text[end:], "…")) {
+			if text[end] == ',' {
+				end++
+			} else {
+				end += len("…")
+			}
+		}
+		if strings.HasPrefix(text[end:], "...") {
+			end += len("...")
+		}
+		for end < len(text) && text[end] == ' ' {
+			end++
+		}
+
+		spans = append(spans, span{start: t.start, end: end})
+	}
+	return applySpans(text, spans)
+}
+
+// isFiller reports whether word is a filler, including stretched variants like "yyyy" or "hmmm"
+func (s *fillerStage) isFiller(word string) bool {
+	if s.words[word] {
+		return true
+	}
+	return isRepeated(word, 'y', 2) || isRepeated(word, 'e', 3) || isRepeated(word, 'm', 2) ||
+		(strings.HasPrefix(word, "h") && isRepeated(word[1:], 'm', 2)) ||
+		(strings.HasPrefix(word, "u") && isRepeated(word[1:], 'm', 1))
+}
+
+// isRepeated reports whether word consists of at least min copies of r
+func isRepeated(word string, r byte, min int) bool {
+	if len(word) < min {
+		return false
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPhrase returns the first phrase that matches tokens at i
+func matchPhrase(phrases []phraseReplacement, tokens []token, i int) *phraseReplacement {
+	for j := range phrases {
+		if matchWords(tokens, i, phrases[j].words) {
+			return &phrases[j]
+		}
+	}
+	return nil
+}
+
+// sortLongestFirst orders phrases so multi-word phrases win over their prefixes
+func sortLongestFirst(phrases []phraseReplacement) {
+	sort.SliceStable(phrases, func(i, j int) bool {
+		return len(phrases[i].words) > len(phrases[j].words)
+	})
+}
@@ -0,0 +1,86 @@
+package postprocess
+
+import (
+	"strings"
+	"unicode"
+)
+
+// token is a word with its byte span in the source text
+type token struct {
+	word  string
+	start int
+	end   int
+}
+
+// span is a byte range of text to replace
+type span struct {
+	start       int
+	end         int
+	replacement string
+}
+
+// tokenize splits text into lowercase words with their byte spans
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\''
+		if isWordRune && start < 0 {
+			start = i
+		} else if !isWordRune && start >= 0 {
+			tokens = append(tokens, token{word: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{word: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// splitWords lowercases a phrase and splits it into words the way tokenize does
+func splitWords(phrase string) []string {
+	tokens := tokenize(phrase)
+	words := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		words = append(words, t.word)
+	}
+	return words
+}
+
+// matchWords reports whether words appear in tokens starting at i
+func matchWords(tokens []token, i int, words []string) bool {
+	if len(words) == 0 || i+len(words) > len(tokens) {
+		return false
+	}
+	for j, word := range words {
+		if tokens[i+j].word != word {
+			return false
+		}
+	}
+	return true
+}
+
+// applySpans replaces spans in text. Spans must be ordered by start; overlapping
+// parts of later spans are dropped.
+func applySpans(text string, spans []span) string {
+	if len(spans) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		if s.end <= last {
+			continue
+		}
+		if s.start < last {
+			s.start = last
+		}
+		b.WriteString(text[last:s.start])
+		b.WriteString(s.replacement)
+		last = s.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
@@ -0,0 +1,178 @@
+// Package redact masks personal data and secrets (emails, phone numbers,
+// IBANs, card numbers, API keys) before text leaves the machine, and puts
+// the original values back into the LLM response.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dooshek/voicify/internal/types"
+)
+
+// Built-in categories
+const (
+	CategoryEmail  = "email"
+	CategoryPhone  = "phone"
+	CategoryIBAN   = "iban"
+	CategoryCard   = "card"
+	CategoryAPIKey = "api_key"
+)
+
+// BlockedError is returned when text contains a category listed as strict
+type BlockedError struct {
+	Category string
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("text contains %s data, which redaction is configured never to send", e.Category)
+}
+
+// rule detects one category
+type rule struct {
+	category string
+	pattern  *regexp.Regexp
+	// valid filters out false positives, e.g. card numbers failing the Luhn check
+	valid func(match string) bool
+}
+
+// Redactor replaces sensitive values with placeholders. A nil Redactor
+// passes text through unchanged.
+type Redactor struct {
+	rules  []rule
+	strict map[string]bool
+}
+
+// New builds a redactor from the config. It returns nil when redaction is disabled.
+func New(cfg types.RedactionConfig) (*Redactor, error) {
+	if cfg.Disabled {
+		return nil, nil
+	}
+
+	r := &Redactor{strict: make(map[string]bool)}
+	enabled := make(map[string]bool)
+	for _, category := range cfg.Categories {
+		enabled[category] = true
+	}
+	for _, builtin := range builtinRules {
+		if len(enabled) == 0 || enabled[builtin.category] {
+			r.rules = append(r.rules, builtin)
+		}
+	}
+
+	for _, p := range cfg.Patterns {
+		if p.Name == "" {
+			return nil, fmt.Errorf("redaction pattern %q has no name", p.Regex)
+		}
+		pattern, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p.Name, err)
+		}
+		r.rules = append(r.rules, rule{category: p.Name, pattern: pattern})
+	}
+
+	for _, category := range cfg.Strict {
+		r.strict[category] = true
+	}
+	return r, nil
+}
+
+// Redact replaces sensitive values in text with placeholders such as
+// [EMAIL_1], recording them in m so the same value always gets the same
+// placeholder and Restore can put it back. It fails with a *BlockedError
+// when text contains a strict category.
+func (r *Redactor) Redact(text string, m *Mapping) (string, error) {
+	if r == nil {
+		return text, nil
+	}
+
+	var blocked error
+	for _, rl := range r.rules {
+		text = rl.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if rl.valid != nil && !rl.valid(match) {
+				return match
+			}
+			if r.strict[rl.category] && blocked == nil {
+				blocked = &BlockedError{Category: rl.category}
+			}
+			return m.placeholder(rl.category, match)
+		})
+	}
+	if blocked != nil {
+		return "", blocked
+	}
+	return text, nil
+}
+
+// Scrub masks sensitive values with their category ([EMAIL]) for logging.
+// Strict categories are masked like the others.
+func (r *Redactor) Scrub(text string) string {
+	if r == nil {
+		return text
+	}
+
+	for _, rl := range r.rules {
+		text = rl.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if rl.valid != nil && !rl.valid(match) {
+				return match
+			}
+			return "[" + placeholderName(rl.category) + "]"
+		})
+	}
+	return text
+}
+
+// Mapping remembers the values replaced by placeholders during one request
+type Mapping struct {
+	values   map[string]string // placeholder -> original
+	byValue  map[string]string // original -> placeholder
+	counters map[string]int
+}
+
+// NewMapping creates an empty mapping
+func NewMapping() *Mapping {
+	return &Mapping{
+		values:   make(map[string]string),
+		byValue:  make(map[string]string),
+		counters: make(map[string]int),
+	}
+}
+
+// Len returns the number of distinct redacted values
+func (m *Mapping) Len() int {
+	return len(m.values)
+}
+
+// Restore replaces placeholders in text with the original values
+func (m *Mapping) Restore(text string) string {
+	if len(m.values) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, 2*len(m.values))
+	for placeholder, original := range m.values {
+		pairs = append(pairs, placeholder, original)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+func (m *Mapping) placeholder(category, value string) string {
+	if placeholder, ok := m.byValue[value]; ok {
+		return placeholder
+	}
+	m.counters[category]++
+	placeholder := fmt.Sprintf("[%s_%d]", placeholderName(category), m.counters[category])
+	m.values[placeholder] = value
+	m.byValue[value] = placeholder
+	return placeholder
+}
+
+// placeholderName turns a category into an upper-case placeholder name
+func placeholderName(category string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, category))
+}
@@ -0,0 +1,64 @@
+package redact
+
+import (
+	"regexp"
+	"unicode"
+)
+
+// builtinRules run in order; earlier rules win because their matches are
+// already placeholders when later rules run (IBANs before phone numbers)
+var builtinRules = []rule{
+	{
+		category: CategoryAPIKey,
+		pattern: regexp.MustCompile(`\b(?:sk-(?:proj-)?[A-Za-z0-9_-]{20,}|gsk_[A-Za-z0-9]{20,}|` +
+			`gh[pousr]_[A-Za-z0-9]{30,}|github_pat_[A-Za-z0-9_]{30,}|lin_api_[A-Za-z0-9]{20,}|` +
+			`xox[abprs]-[A-Za-z0-9-]{10,}|AKIA[0-9A-Z]{16}|AIza[0-9A-Za-z_-]{35}|` +
+			`eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})`),
+	},
+	{
+		category: CategoryEmail,
+		pattern:  regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	},
+	{
+		// "PL61 1090 1014 0000 0712 1981 2874" and the Polish 26-digit account number
+		category: CategoryIBAN,
+		pattern: regexp.MustCompile(`\b(?:[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){3,7}(?: ?[A-Z0-9]{1,3})?|` +
+			`\d{2}(?: ?\d{4}){6})\b`),
+	},
+	{
+		category: CategoryCard,
+		pattern:  regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		valid:    luhn,
+	},
+	{
+		// International numbers, "123 456 789", "123-456-789" and "(555) 123-4567".
+		// Local numbers need separators so bare 9-digit IDs, order numbers and
+		// amounts are left alone.
+		category: CategoryPhone,
+		pattern: regexp.MustCompile(`\+\d{1,3}[ -]?\(?\d{1,4}\)?(?:[ -]?\d){6,11}\b|` +
+			`\b\d{3}(?: \d{3} \d{3}|-\d{3}-\d{3})\b|\(\d{3}\) ?\d{3}-\d{4}\b|\b\d{3}-\d{3}-\d{4}\b`),
+	},
+}
+
+// luhn reports whether the digits in number pass the Luhn checksum
+func luhn(number string) bool {
+	sum, digits := 0, 0
+	double := false
+	runes := []rune(number)
+	for i := len(runes) - 1; i >= 0; i-- {
+		if !unicode.IsDigit(runes[i]) {
+			continue
+		}
+		d := int(runes[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
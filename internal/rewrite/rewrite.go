@@ -0,0 +1,155 @@
+package rewrite
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dooshek/voicify/internal/llm"
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/internal/types"
+)
+
+const defaultTimeout = 3 * time.Second
+
+var errEmptyResponse = errors.New("empty rewrite response")
+
+// Result describes the outcome of a rewrite attempt
+type Result struct {
+	// Raw is the text before rewriting
+	Raw string
+	// Text is the rewritten text, or Raw when no rewrite was applied
+	Text string
+	// Profile is the name of the matched profile, empty when none matched
+	Profile string
+	// Rewritten is true when Text came from the LLM
+	Rewritten bool
+}
+
+// Notifier is told about every text prepared for pasting; the D-Bus server
+// implements it to report the raw and rewritten text with TranscriptionReady
+type Notifier interface {
+	RecordPaste(result Result)
+}
+
+// ForPaste rewrites text that is about to be pasted and reports it to the
+// D-Bus server, if one runs. Routing sees the raw text, so only the actions
+// inserting the transcription use it.
+func ForPaste(text string) string {
+	result := Apply(text)
+	if notifier, ok := state.Get().GetDBusServer().(Notifier); ok {
+		notifier.RecordPaste(result)
+	}
+	return result.Text
+}
+
+// Apply rewrites text with the profile matching the focused window. On any
+// failure, including the timeout, the raw text is returned unchanged.
+func Apply(text string) Result {
+	result := Result{Raw: text, Text: text}
+
+	appState := state.Get()
+	appCfg := appState.GetConfig()
+	if appCfg == nil || strings.TrimSpace(text) == "" {
+		return result
+	}
+	cfg := appCfg.LLM.Rewrite
+	if !cfg.Enabled {
+		return result
+	}
+
+	title, app := appState.GetFocusedWindow()
+	profile := SelectProfile(cfg.Profiles, title, app)
+	if profile == nil {
+		logger.Debugf("Rewrite: no profile matches window %q (%s)", title, app)
+		return result
+	}
+	result.Profile = profile.Name
+
+	rewritten, err := complete(cfg, profile, text)
+	if err != nil {
+		logger.Warnf("Rewrite: profile %q failed, using raw text: %v", profile.Name, err)
+		return result
+	}
+
+	logger.Debugf("Rewrite [%s]: %q -> %q", profile.Name, text, rewritten)
+	result.Text = rewritten
+	result.Rewritten = true
+	return result
+}
+
+// SelectProfile returns the first profile whose title and app patterns match.
+// A profile without patterns matches every window.
+func SelectProfile(profiles []types.RewriteProfile, title, app string) *types.RewriteProfile {
+	for i := range profiles {
+		profile := &profiles[i]
+		if matches(profile.Title, title, profile.Name) && matches(profile.App, app, profile.Name) {
+			return profile
+		}
+	}
+	return nil
+}
+
+// matches reports whether value matches pattern; an empty pattern matches anything
+func matches(pattern, value, profileName string) bool {
+	if pattern == "" {
+		return true
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		logger.Warnf("Rewrite: invalid pattern %q in profile %q: %v", pattern, profileName, err)
+		return false
+	}
+	return re.MatchString(value)
+}
+
+// complete runs the profile prompt through the configured provider
+func complete(cfg types.LLMRewrite, profile *types.RewriteProfile, text string) (string, error) {
+	providerType := types.LLMProvider(cfg.Provider)
+	if providerType == "" {
+		providerType = state.Get().GetRouterProvider()
+	}
+	provider, err := llm.NewProvider(providerType)
+	if err != nil {
+		return "", err
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = state.Get().GetRouterModel()
+	}
+
+	timeout := defaultTimeout
+	if cfg.TimeoutMs > 0 {
+		timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
+	}
+	if profile.TimeoutMs > 0 {
+		timeout = time.Duration(profile.TimeoutMs) * time.Millisecond
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req := llm.CompletionRequest{
+		Model: model,
+		Messages: []llm.ChatCompletionMessage{
+			{Role: "system", Content: profile.Prompt},
+			{Role: "user", Content: text},
+		},
+		Temperature: float32(cfg.Temperature),
+	}
+
+	response, err := provider.Completion(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	response = strings.TrimSpace(response)
+	if response == "" {
+		return "", errEmptyResponse
+	}
+	return response, nil
+}
@@ -0,0 +1,85 @@
+package spelling
+
+// itemKind is the kind of a spoken spelling token
+type itemKind int
+
+const (
+	letterItem  itemKind = iota // a letter, affected by case commands
+	literalItem                 // a digit or symbol written as is
+	capitalItem                 // uppercases the next letter
+	capsOnItem                  // uppercases all following letters
+	capsOffItem                 // ends capsOnItem
+	wordItem                    // an unrecognized word, inserted verbatim
+)
+
+// letterNames map spoken letter names to letters: the NATO alphabet, English
+// and Polish letter names. Single-letter tokens ("b", "ł") need no entry.
+var letterNames = map[string]string{
+	"alpha": "a", "alfa": "a", "bravo": "b", "charlie": "c", "delta": "d",
+	"echo": "e", "foxtrot": "f", "golf": "g", "hotel": "h", "india": "i",
+	"juliet": "j", "juliett": "j", "kilo": "k", "lima": "l", "mike": "m",
+	"november": "n", "oscar": "o", "papa": "p", "quebec": "q", "romeo": "r",
+	"sierra": "s", "tango": "t", "uniform": "u", "victor": "v", "whiskey": "w",
+	"whisky": "w", "x-ray": "x", "xray": "x", "x ray": "x", "yankee": "y", "zulu": "z",
+
+	"bee": "b", "cee": "c", "see": "c", "dee": "d", "ef": "f", "gee": "g",
+	"aitch": "h", "jay": "j", "kay": "k", "el": "l", "em": "m", "en": "n",
+	"pee": "p", "cue": "q", "queue": "q", "ar": "r", "es": "s", "tee": "t",
+	"vee": "v", "double you": "w", "double u": "w", "ex": "x", "wye": "y",
+	"why": "y", "zee": "z", "zed": "z",
+
+	"be": "b", "ce": "c", "de": "d", "gie": "g", "ha": "h", "jot": "j",
+	"ka": "k", "pe": "p", "ku": "q", "er": "r", "te": "t", "fau": "v",
+	"wu": "w", "iks": "x", "igrek": "y", "zet": "z",
+	"a z ogonkiem": "ą", "a ogonek": "ą", "cie": "ć", "ce z kreską": "ć",
+	"e z ogonkiem": "ę", "e ogonek": "ę", "eł": "ł", "eń": "ń", "en z kreską": "ń",
+	"o z kreską": "ó", "o kreskowane": "ó", "u zamknięte": "ó", "u otwarte": "u",
+	"eś": "ś", "es z kreską": "ś", "ziet": "ź", "zet z kreską": "ź",
+	"żet": "ż", "zet z kropką": "ż",
+}
+
+// literals map spoken digits and separators to the characters they stand for
+var literals = map[string]string{
+	"zero": "0", "one": "1", "two": "2", "three": "3", "four": "4",
+	"five": "5", "six": "6", "seven": "7", "eight": "8", "nine": "9", "niner": "9",
+	"jeden": "1", "dwa": "2", "trzy": "3", "cztery": "4", "pięć": "5",
+	"sześć": "6", "siedem": "7", "osiem": "8", "dziewięć": "9",
+
+	"dash": "-", "hyphen": "-", "minus": "-", "myślnik": "-", "łącznik": "-",
+	"underscore": "_", "under score": "_", "podkreślnik": "_", "podłoga": "_",
+	"dot": ".", "period": ".", "point": ".", "kropka": ".",
+	"at": "@", "at sign": "@", "małpa": "@", "małpka": "@",
+	"slash": "/", "ukośnik": "/", "backslash": `\`, "back slash": `\`,
+	"plus": "+", "hash": "#", "hashtag": "#", "krzyżyk": "#",
+	"colon": ":", "dwukropek": ":", "space": " ", "spacja": " ",
+}
+
+// caseCommands map spoken case commands to their effect
+var caseCommands = map[string]itemKind{
+	"capital": capitalItem, "cap": capitalItem, "uppercase": capitalItem,
+	"upper case": capitalItem, "big": capitalItem,
+	"wielka": capitalItem, "wielkie": capitalItem, "duża": capitalItem, "duże": capitalItem,
+	"wielka litera": capitalItem, "duża litera": capitalItem,
+
+	"all caps": capsOnItem, "caps on": capsOnItem, "caps lock": capsOnItem, "caps lock on": capsOnItem,
+	"same wielkie": capsOnItem, "wszystkie wielkie": capsOnItem, "wielkie litery": capsOnItem,
+
+	"caps off": capsOffItem, "caps lock off": capsOffItem, "no caps": capsOffItem,
+	"end caps": capsOffItem, "lowercase": capsOffItem, "lower case": capsOffItem,
+	"małe litery": capsOffItem, "koniec wielkich": capsOffItem,
+}
+
+// commandPrefixes start a one-off spelled utterance: "spell alpha bravo"
+var commandPrefixes = []string{"spell out", "spell", "przeliteruj", "literuj"}
+
+// modeOnPhrases and modeOffPhrases toggle spelling mode when spoken on their own
+var (
+	modeOnPhrases = map[string]bool{
+		"spelling mode": true, "spelling mode on": true, "start spelling": true,
+		"tryb literowania": true, "włącz literowanie": true, "zacznij literować": true,
+	}
+	modeOffPhrases = map[string]bool{
+		"spelling mode off": true, "stop spelling": true, "end spelling": true,
+		"koniec literowania": true, "wyłącz literowanie": true, "zakończ literowanie": true,
+	}
+)
@@ -0,0 +1,261 @@
+// Package spelling assembles spelled-out input into an exact character string:
+// "capital alpha bravo dash one two" -> "Ab-12". It understands single letters,
+// the NATO alphabet, English and Polish letter names, digits, a few separators
+// and case commands ("capital", "all caps").
+package spelling
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/state"
+)
+
+// item is a parsed spelling token
+type item struct {
+	kind itemKind
+	text string
+}
+
+// phrase is a compiled vocabulary entry
+type phrase struct {
+	words []string
+	item  item
+}
+
+var phrases = compilePhrases()
+
+// Requested reports whether text is meant for spelling: spelling mode is on,
+// or text is a spelling command. It has no side effects, so callers can use
+// it to keep spelled input away from post-processing and LLM rewriting.
+func Requested(text string) bool {
+	if state.Get().IsSpellingMode() {
+		return true
+	}
+	words := splitWords(text)
+	if isModePhrase(words) {
+		return true
+	}
+	_, ok := commandRest(words)
+	return ok
+}
+
+// Interpret handles an utterance meant for spelling. Mode phrases switch
+// spelling mode on or off and yield no text; while the mode is on every
+// utterance is spelled; otherwise only "spell ..." commands are. handled is
+// false when text is ordinary dictation.
+func Interpret(text string) (spelled string, handled bool) {
+	words := splitWords(text)
+	joined := strings.ToLower(strings.Join(words, " "))
+
+	switch {
+	case modeOnPhrases[joined]:
+		state.Get().SetSpellingMode(true)
+		logger.Info("Spelling mode enabled")
+		return "", true
+	case modeOffPhrases[joined]:
+		state.Get().SetSpellingMode(false)
+		logger.Info("Spelling mode disabled")
+		return "", true
+	}
+
+	if state.Get().IsSpellingMode() {
+		return Spell(text), true
+	}
+
+	rest, ok := commandRest(words)
+	if !ok {
+		return "", false
+	}
+	return Spell(strings.Join(rest, " ")), true
+}
+
+// Spell assembles the spoken tokens in text into a character string
+func Spell(text string) string {
+	return render(parse(splitWords(text)))
+}
+
+// commandRest returns the words after a spelling command prefix. The rest must
+// be mostly spelling vocabulary, so "spell check is broken" stays dictation.
+func commandRest(words []string) ([]string, bool) {
+	for _, prefix := range commandPrefixes {
+		prefixWords := strings.Fields(prefix)
+		if len(words) <= len(prefixWords) || !hasPrefix(words, prefixWords) {
+			continue
+		}
+		rest := words[len(prefixWords):]
+		items := parse(rest)
+		recognized := 0
+		for _, it := range items {
+			if it.kind != wordItem {
+				recognized++
+			}
+		}
+		return rest, recognized*2 >= len(items)
+	}
+	return nil, false
+}
+
+func isModePhrase(words []string) bool {
+	joined := strings.ToLower(strings.Join(words, " "))
+	return modeOnPhrases[joined] || modeOffPhrases[joined]
+}
+
+func hasPrefix(words, prefix []string) bool {
+	for i, word := range prefix {
+		if strings.ToLower(words[i]) != word {
+			return false
+		}
+	}
+	return true
+}
+
+// parse turns words into spelling items, longest phrases first
+func parse(words []string) []item {
+	var items []item
+	for i := 0; i < len(words); {
+		if p := matchPhrase(words, i); p != nil {
+			items = append(items, p.item)
+			i += len(p.words)
+			continue
+		}
+		items = append(items, classify(words[i]))
+		i++
+	}
+	return items
+}
+
+// classify reads a word that is not in the vocabulary
+func classify(word string) item {
+	runes := []rune(word)
+	switch {
+	case len(runes) == 1 && unicode.IsLetter(runes[0]):
+		return item{kind: letterItem, text: strings.ToLower(word)}
+	case isDigits(word), isCode(word):
+		return item{kind: literalItem, text: word}
+	}
+	return item{kind: wordItem, text: word}
+}
+
+// render writes items, applying case commands to letters and words
+func render(items []item) string {
+	var b strings.Builder
+	capitalNext, allCaps := false, false
+	for _, it := range items {
+		switch it.kind {
+		case capitalItem:
+			capitalNext = true
+		case capsOnItem:
+			allCaps = true
+		case capsOffItem:
+			allCaps = false
+		case literalItem:
+			b.WriteString(it.text)
+		case letterItem:
+			if capitalNext || allCaps {
+				b.WriteString(strings.ToUpper(it.text))
+			} else {
+				b.WriteString(it.text)
+			}
+			capitalNext = false
+		case wordItem:
+			switch {
+			case allCaps:
+				b.WriteString(strings.ToUpper(it.text))
+			case capitalNext:
+				runes := []rune(it.text)
+				runes[0] = unicode.ToUpper(runes[0])
+				b.WriteString(string(runes))
+			default:
+				b.WriteString(it.text)
+			}
+			capitalNext = false
+		}
+	}
+	return b.String()
+}
+
+// matchPhrase returns the longest vocabulary phrase at i
+func matchPhrase(words []string, i int) *phrase {
+	for k := range phrases {
+		p := &phrases[k]
+		if i+len(p.words) > len(words) {
+			continue
+		}
+		matched := true
+		for j, word := range p.words {
+			if strings.ToLower(words[i+j]) != word {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return p
+		}
+	}
+	return nil
+}
+
+// compilePhrases builds the phrase list from the vocabulary, longest first
+func compilePhrases() []phrase {
+	var compiled []phrase
+	for spoken, letter := range letterNames {
+		compiled = append(compiled, phrase{words: strings.Fields(spoken), item: item{kind: letterItem, text: letter}})
+	}
+	for spoken, literal := range literals {
+		compiled = append(compiled, phrase{words: strings.Fields(spoken), item: item{kind: literalItem, text: literal}})
+	}
+	for spoken, kind := range caseCommands {
+		compiled = append(compiled, phrase{words: strings.Fields(spoken), item: item{kind: kind}})
+	}
+	sort.SliceStable(compiled, func(i, j int) bool {
+		if len(compiled[i].words) != len(compiled[j].words) {
+			return len(compiled[i].words) > len(compiled[j].words)
+		}
+		return strings.Join(compiled[i].words, " ") < strings.Join(compiled[j].words, " ")
+	})
+	return compiled
+}
+
+// splitWords splits text on spaces and commas and trims sentence punctuation,
+// keeping words like "ABC-1234" whole. A lone symbol ("-", "@") is kept as is.
+func splitWords(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		word := strings.Trim(field, `.!?;:"()`)
+		if word == "" {
+			word = field
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// isCode reports whether word was already transcribed as an exact string:
+// no lowercase letters and at least one letter or digit ("ABC-1234", "A1")
+func isCode(word string) bool {
+	hasAlnum := false
+	for _, r := range word {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			hasAlnum = true
+		}
+	}
+	return hasAlnum
+}
+
+func isDigits(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return word != ""
+}
@@ -12,7 +12,7 @@ var (
 )
 
 type AppState struct {
-	Config          *types.Config
+	config          *types.Config
 	ttsManager      interface{} // Use interface{} to avoid import cycle, will be *tts.Manager
 	router          interface{} // Use interface{} to avoid import cycle, will be *transcriptionrouter.Router
 	linearMCPClient interface{} // Use interface{} to avoid import cycle, will be *linear.LinearMCPClient
@@ -20,13 +20,16 @@ type AppState struct {
 	// Focused window cache provided by GNOME extension via D-Bus
 	focusedWindowTitle string
 	focusedWindowApp   string
-	mu                 sync.RWMutex
+	// spellingMode makes every routed utterance a spelled character string
+	spellingMode  bool
+	recordingMode types.RecordingMode
+	mu            sync.RWMutex
 }
 
 func Init(cfg *types.Config) {
 	once.Do(func() {
 		instance = &AppState{
-			Config: cfg,
+			config: cfg,
 		}
 	})
 }
@@ -38,6 +41,35 @@ func Get() *AppState {
 	return instance
 }
 
+// GetConfig returns the current configuration. The returned config is not
+// modified afterwards; changes go through SetConfig or UpdateConfig.
+func (s *AppState) GetConfig() *types.Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// SetConfig replaces the configuration, e.g. after the config file was
+// reloaded
+func (s *AppState) SetConfig(cfg *types.Config) {
+	s.mu.Lock()
+	s.config = cfg
+	s.mu.Unlock()
+}
+
+// UpdateConfig applies update to a copy of the configuration and replaces
+// the configuration with it, so readers never see a half updated config
+func (s *AppState) UpdateConfig(update func(cfg *types.Config)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg := types.Config{}
+	if s.config != nil {
+		cfg = *s.config
+	}
+	update(&cfg)
+	s.config = &cfg
+}
+
 // SetTTSManager sets the TTS manager in the global state
 func (s *AppState) SetTTSManager(manager interface{}) {
 	s.ttsManager = manager
@@ -49,24 +81,28 @@ func (s *AppState) GetTTSManager() interface{} {
 }
 
 func (s *AppState) GetTranscriptionProvider() types.LLMProvider {
-	return types.LLMProvider(s.Config.LLM.Transcription.Provider)
+	return types.LLMProvider(s.GetConfig().LLM.Transcription.Provider)
 }
 
 func (s *AppState) GetRouterProvider() types.LLMProvider {
-	return types.LLMProvider(s.Config.LLM.Router.Provider)
+	return types.LLMProvider(s.GetConfig().LLM.Router.Provider)
 }
 
 func (s *AppState) GetRouterModel() string {
-	return s.Config.LLM.Router.Model
+	return s.GetConfig().LLM.Router.Model
 }
 
 // SetRouter sets the global router in the state
 func (s *AppState) SetRouter(router interface{}) {
+	s.mu.Lock()
 	s.router = router
+	s.mu.Unlock()
 }
 
 // GetRouter returns the global router from state
 func (s *AppState) GetRouter() interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.router
 }
 
@@ -106,3 +142,31 @@ func (s *AppState) SetDBusServer(server interface{}) {
 func (s *AppState) GetDBusServer() interface{} {
 	return s.dbusServer
 }
+
+// SetSpellingMode switches spelling mode on or off
+func (s *AppState) SetSpellingMode(enabled bool) {
+	s.mu.Lock()
+	s.spellingMode = enabled
+	s.mu.Unlock()
+}
+
+// IsSpellingMode reports whether spelling mode is on
+func (s *AppState) IsSpellingMode() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.spellingMode
+}
+
+// SetRecordingMode records how the current recording was started
+func (s *AppState) SetRecordingMode(mode types.RecordingMode) {
+	s.mu.Lock()
+	s.recordingMode = mode
+	s.mu.Unlock()
+}
+
+// GetRecordingMode returns how the current recording was started
+func (s *AppState) GetRecordingMode() types.RecordingMode {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.recordingMode
+}
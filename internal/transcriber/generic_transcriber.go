@@ -0,0 +1,411 @@
+package transcriber
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/state"
+	"github.com/gorilla/websocket"
+)
+
+// genericDrainTimeout bounds how long Stop waits for the server to finish
+// transcribing audio that was already sent
+const genericDrainTimeout = 5 * time.Second
+
+// GenericStreamingTranscriber streams audio to a self-hosted ASR server.
+//
+// Protocol: the session is opened on the configured URL with query parameters
+// sample_rate, encoding (pcm_s16le), channels and, when set, model and language.
+// For ws:// and wss:// URLs audio is sent as binary WebSocket frames; for
+// http:// and https:// URLs it is sent as a chunked POST body. The server
+// replies with JSON messages (WebSocket text frames, or newline-delimited JSON
+// in the HTTP response). When recording stops the client ends the audio by
+// closing the request body or sending a WebSocket close frame; the server
+// sends the remaining final messages and then ends the response or closes
+// the connection.
+//
+//	{"type": "partial", "id": "seg-1", "delta": "hel"}
+//	{"type": "final",   "id": "seg-1", "text": "hello world"}
+//	{"type": "error",   "message": "..."}
+type GenericStreamingTranscriber struct {
+	endpoint       string
+	apiKey         string
+	model          string
+	mu             sync.Mutex
+	writeMu        sync.Mutex // serializes audio writes, which happen outside mu
+	isActive       bool
+	audioEnded     bool
+	wsConn         *websocket.Conn
+	httpBody       *io.PipeWriter
+	transcriptChan chan TranscriptEvent
+	partialChan    chan TranscriptEvent
+	errorChan      chan error
+	ctx            context.Context
+	cancel         context.CancelFunc
+	// done is closed when the session's reader goroutine exits
+	done chan struct{}
+	// segment order as announced by the server
+	lastItemID    string
+	previousItems map[string]string
+}
+
+// genericMessage is a server message of the generic streaming protocol
+type genericMessage struct {
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	Delta   string `json:"delta,omitempty"`
+	Text    string `json:"text,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// NewGenericStreamingTranscriber creates a transcriber for the generic protocol
+func NewGenericStreamingTranscriber(endpoint string, apiKey string) (*GenericStreamingTranscriber, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("generic streaming backend requires a URL")
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid streaming URL: %w", err)
+	}
+	switch u.Scheme {
+	case "ws", "wss", "http", "https":
+	default:
+		return nil, fmt.Errorf("unsupported streaming URL scheme: %s", u.Scheme)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &GenericStreamingTranscriber{
+		endpoint:       endpoint,
+		apiKey:         apiKey,
+		transcriptChan: make(chan TranscriptEvent, 100),
+		partialChan:    make(chan TranscriptEvent, 100),
+		errorChan:      make(chan error, 10),
+		ctx:            ctx,
+		cancel:         cancel,
+		previousItems:  make(map[string]string),
+	}, nil
+}
+
+// Start opens the streaming session
+func (gt *GenericStreamingTranscriber) Start() error {
+	gt.mu.Lock()
+	defer gt.mu.Unlock()
+
+	if gt.isActive {
+		return fmt.Errorf("streaming transcriber already active")
+	}
+
+	gt.ctx, gt.cancel = context.WithCancel(context.Background())
+	gt.done = make(chan struct{})
+	gt.audioEnded = false
+	gt.lastItemID = ""
+	gt.previousItems = make(map[string]string)
+
+	sessionURL, err := gt.sessionURL()
+	if err != nil {
+		return err
+	}
+
+	if sessionURL.Scheme == "ws" || sessionURL.Scheme == "wss" {
+		err = gt.connectWebSocket(sessionURL)
+	} else {
+		err = gt.connectHTTP(sessionURL)
+	}
+	if err != nil {
+		gt.cancel()
+		return err
+	}
+
+	gt.isActive = true
+	logger.Infof("🎙️ Streaming transcription started (%s)", sessionURL.Host)
+	return nil
+}
+
+// Stop ends the audio stream and waits up to genericDrainTimeout for the
+// server to send the remaining segments before closing the session
+func (gt *GenericStreamingTranscriber) Stop() {
+	gt.mu.Lock()
+	if !gt.isActive {
+		gt.mu.Unlock()
+		return
+	}
+	done := gt.done
+	gt.endAudio()
+	gt.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(genericDrainTimeout):
+		logger.Warnf("Streaming server did not finish within %s, dropping pending segments", genericDrainTimeout)
+	}
+	gt.Cancel()
+}
+
+// Cancel closes the streaming session without waiting for pending segments
+func (gt *GenericStreamingTranscriber) Cancel() {
+	gt.mu.Lock()
+	defer gt.mu.Unlock()
+
+	if !gt.isActive {
+		return
+	}
+
+	gt.isActive = false
+	gt.cancel()
+
+	if gt.wsConn != nil {
+		gt.wsConn.Close()
+		gt.wsConn = nil
+	}
+	if gt.httpBody != nil {
+		gt.httpBody.Close()
+		gt.httpBody = nil
+	}
+
+	logger.Infof("🎙️ Streaming transcription stopped")
+}
+
+// endAudio tells the server no more audio follows. Must be called with gt.mu held.
+func (gt *GenericStreamingTranscriber) endAudio() {
+	if gt.audioEnded {
+		return
+	}
+	gt.audioEnded = true
+
+	if gt.wsConn != nil {
+		msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		deadline := time.Now().Add(time.Second)
+		if err := gt.wsConn.WriteControl(websocket.CloseMessage, msg, deadline); err != nil {
+			logger.Warnf("Failed to end streaming audio: %v", err)
+		}
+	}
+	if gt.httpBody != nil {
+		gt.httpBody.Close()
+	}
+}
+
+// SendAudio streams PCM audio to the server. Writes block while the server
+// is not reading, so they happen without holding gt.mu; Cancel closes the
+// connection to unblock them.
+func (gt *GenericStreamingTranscriber) SendAudio(pcmData []byte) error {
+	gt.mu.Lock()
+	active := gt.isActive && !gt.audioEnded
+	wsConn, httpBody := gt.wsConn, gt.httpBody
+	gt.mu.Unlock()
+
+	if !active {
+		return fmt.Errorf("transcriber not active")
+	}
+
+	gt.writeMu.Lock()
+	defer gt.writeMu.Unlock()
+
+	if wsConn != nil {
+		return wsConn.WriteMessage(websocket.BinaryMessage, pcmData)
+	}
+	if httpBody != nil {
+		_, err := httpBody.Write(pcmData)
+		return err
+	}
+	return fmt.Errorf("transcriber not connected")
+}
+
+// SetModel sets the transcription model for the next session
+func (gt *GenericStreamingTranscriber) SetModel(model string) {
+	gt.mu.Lock()
+	defer gt.mu.Unlock()
+	gt.model = model
+}
+
+// TranscriptChan returns channel for completed segments
+func (gt *GenericStreamingTranscriber) TranscriptChan() <-chan TranscriptEvent {
+	return gt.transcriptChan
+}
+
+// PartialChan returns channel for partial transcript deltas
+func (gt *GenericStreamingTranscriber) PartialChan() <-chan TranscriptEvent {
+	return gt.partialChan
+}
+
+// ErrorChan returns channel for errors
+func (gt *GenericStreamingTranscriber) ErrorChan() <-chan error {
+	return gt.errorChan
+}
+
+// sessionURL builds the endpoint URL with audio format parameters
+func (gt *GenericStreamingTranscriber) sessionURL() (*url.URL, error) {
+	u, err := url.Parse(gt.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid streaming URL: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("sample_rate", strconv.Itoa(24000))
+	q.Set("encoding", "pcm_s16le")
+	q.Set("channels", "1")
+	if gt.model != "" {
+		q.Set("model", gt.model)
+	}
+	if language := state.Get().GetConfig().LLM.Transcription.Language; language != "" {
+		q.Set("language", language)
+	}
+	u.RawQuery = q.Encode()
+	return u, nil
+}
+
+// authHeader returns request headers with the optional bearer token
+func (gt *GenericStreamingTranscriber) authHeader() http.Header {
+	header := http.Header{}
+	if gt.apiKey != "" {
+		header.Add("Authorization", "Bearer "+gt.apiKey)
+	}
+	return header
+}
+
+// connectWebSocket opens a WebSocket session and starts reading messages
+func (gt *GenericStreamingTranscriber) connectWebSocket(u *url.URL) error {
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 30 * time.Second,
+	}
+
+	conn, _, err := dialer.Dial(u.String(), gt.authHeader())
+	if err != nil {
+		return fmt.Errorf("failed to dial WebSocket: %w", err)
+	}
+	gt.wsConn = conn
+
+	go func(ctx context.Context, done chan struct{}) {
+		defer close(done)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				if ctx.Err() == nil && websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					logger.Errorf("Streaming WebSocket error", err)
+					gt.sendError(err)
+				}
+				return
+			}
+			gt.handleMessage(ctx, message)
+		}
+	}(gt.ctx, gt.done)
+
+	return nil
+}
+
+// connectHTTP opens a chunked POST session and starts reading NDJSON responses
+func (gt *GenericStreamingTranscriber) connectHTTP(u *url.URL) error {
+	bodyReader, bodyWriter := io.Pipe()
+
+	req, err := http.NewRequestWithContext(gt.ctx, http.MethodPost, u.String(), bodyReader)
+	if err != nil {
+		return fmt.Errorf("failed to create streaming request: %w", err)
+	}
+	req.Header = gt.authHeader()
+	req.Header.Set("Content-Type", "audio/l16; rate=24000; channels=1")
+	gt.httpBody = bodyWriter
+
+	go func(ctx context.Context, done chan struct{}) {
+		defer close(done)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Errorf("Streaming HTTP request failed", err)
+				gt.sendError(err)
+			}
+			bodyReader.CloseWithError(err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			err := fmt.Errorf("streaming server returned status %d", resp.StatusCode)
+			gt.sendError(err)
+			bodyReader.CloseWithError(err)
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if len(scanner.Bytes()) == 0 {
+				continue
+			}
+			gt.handleMessage(ctx, scanner.Bytes())
+		}
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			logger.Errorf("Streaming HTTP response error", err)
+			gt.sendError(err)
+		}
+	}(gt.ctx, gt.done)
+
+	return nil
+}
+
+// handleMessage decodes a server message and forwards it to channels
+func (gt *GenericStreamingTranscriber) handleMessage(ctx context.Context, data []byte) {
+	var msg genericMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		logger.Errorf("Failed to unmarshal streaming message", err)
+		return
+	}
+
+	switch msg.Type {
+	case "partial":
+		if msg.Delta == "" {
+			return
+		}
+		select {
+		case gt.partialChan <- gt.newTranscriptEvent(msg.ID, msg.Delta):
+		default:
+			// Drop if channel is full - the final message carries the full text
+		}
+	case "final":
+		if msg.Text == "" {
+			return
+		}
+		logger.Debugf("📝 Complete transcript [%s]: %s", msg.ID, msg.Text)
+		select {
+		case gt.transcriptChan <- gt.newTranscriptEvent(msg.ID, msg.Text):
+		case <-ctx.Done():
+		}
+	case "error":
+		gt.sendError(fmt.Errorf("streaming server error: %s", msg.Message))
+	default:
+		logger.Debugf("Received streaming message: %s", msg.Type)
+	}
+}
+
+// newTranscriptEvent builds an event, linking the item to the one announced before it
+func (gt *GenericStreamingTranscriber) newTranscriptEvent(itemID, text string) TranscriptEvent {
+	gt.mu.Lock()
+	defer gt.mu.Unlock()
+
+	if _, seen := gt.previousItems[itemID]; !seen {
+		gt.previousItems[itemID] = gt.lastItemID
+		gt.lastItemID = itemID
+	}
+	return TranscriptEvent{
+		ItemID:         itemID,
+		PreviousItemID: gt.previousItems[itemID],
+		Text:           text,
+	}
+}
+
+// sendError forwards an error without blocking
+func (gt *GenericStreamingTranscriber) sendError(err error) {
+	select {
+	case gt.errorChan <- err:
+	default:
+		// Drop if channel is full
+	}
+}
@@ -0,0 +1,167 @@
+package transcriber
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/internal/types"
+	"github.com/gorilla/websocket"
+)
+
+func TestMain(m *testing.M) {
+	state.Init(&types.Config{})
+	os.Exit(m.Run())
+}
+
+// fakeFinal is the final message the fake servers send for the received audio
+func fakeFinal(audio []byte) string {
+	return fmt.Sprintf(`{"type": "final", "id": "seg-1", "text": "received %d bytes"}`, len(audio))
+}
+
+func TestGenericStopWaitsForHTTPFinal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("encoding"); got != "pcm_s16le" {
+			t.Errorf("encoding = %q, want pcm_s16le", got)
+		}
+		// The final segment is only sent once the client ended the audio
+		audio, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading audio: %v", err)
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprintln(w, fakeFinal(audio))
+	}))
+	defer server.Close()
+
+	assertStopDelivers(t, server.URL)
+}
+
+func TestGenericStopWaitsForWebSocketFinal(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		// Collect audio until the client's close frame, then send the final
+		// segment before closing the connection
+		var audio []byte
+		conn.SetCloseHandler(func(code int, text string) error {
+			// Answer the close frame only after the final segment
+			return nil
+		})
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					t.Errorf("read audio: %v", err)
+					return
+				}
+				break
+			}
+			audio = append(audio, data...)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(fakeFinal(audio))); err != nil {
+			t.Errorf("write final: %v", err)
+			return
+		}
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	}))
+	defer server.Close()
+
+	assertStopDelivers(t, "ws"+strings.TrimPrefix(server.URL, "http"))
+}
+
+// assertStopDelivers streams audio to endpoint and checks the final segment
+// sent after the audio ended is delivered by the time Stop returns
+func assertStopDelivers(t *testing.T, endpoint string) {
+	t.Helper()
+
+	gt, err := NewGenericStreamingTranscriber(endpoint, "")
+	if err != nil {
+		t.Fatalf("NewGenericStreamingTranscriber: %v", err)
+	}
+	if err := gt.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := gt.SendAudio(make([]byte, 100)); err != nil {
+			t.Fatalf("SendAudio: %v", err)
+		}
+	}
+
+	gt.Stop()
+
+	select {
+	case ev := <-gt.TranscriptChan():
+		if ev.Text != "received 300 bytes" {
+			t.Errorf("final text = %q, want %q", ev.Text, "received 300 bytes")
+		}
+	default:
+		t.Fatal("Stop returned before the final segment arrived")
+	}
+	if err := gt.SendAudio(make([]byte, 100)); err == nil {
+		t.Error("SendAudio after Stop succeeded, want error")
+	}
+}
+
+func TestGenericCancelUnblocksPendingAudio(t *testing.T) {
+	// The server never reads the audio, so writes block once buffers are full
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	gt, err := NewGenericStreamingTranscriber(server.URL, "")
+	if err != nil {
+		t.Fatalf("NewGenericStreamingTranscriber: %v", err)
+	}
+	if err := gt.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	sendErr := make(chan error, 1)
+	go func() {
+		for {
+			if err := gt.SendAudio(make([]byte, 1<<20)); err != nil {
+				sendErr <- err
+				return
+			}
+		}
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	cancelled := make(chan struct{})
+	go func() {
+		gt.Cancel()
+		close(cancelled)
+	}()
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Cancel blocked behind a pending audio write")
+	}
+	select {
+	case <-sendErr:
+	case <-time.After(2 * time.Second):
+		t.Fatal("pending audio write was not unblocked by Cancel")
+	}
+}
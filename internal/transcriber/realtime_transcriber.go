@@ -21,12 +21,14 @@ type RealtimeTranscriber struct {
 	conn           *websocket.Conn
 	mu             sync.Mutex
 	isActive       bool
-	transcriptChan chan string
-	partialChan    chan string
+	transcriptChan chan TranscriptEvent
+	partialChan    chan TranscriptEvent
 	errorChan      chan error
 	ctx            context.Context
 	cancel         context.CancelFunc
 	model          string
+	// previousItems maps item IDs to the item committed before them
+	previousItems map[string]string
 }
 
 // RealtimeTranscriptionSession represents OpenAI session response
@@ -59,13 +61,14 @@ type NoiseReductionConfig struct {
 
 // WebSocket event types
 type WSEvent struct {
-	Type       string `json:"type"`
-	EventID    string `json:"event_id,omitempty"`
-	Audio      string `json:"audio,omitempty"`
-	ItemID     string `json:"item_id,omitempty"`
-	Delta      string `json:"delta,omitempty"`
-	Transcript string `json:"transcript,omitempty"`
-	Error      *struct {
+	Type           string `json:"type"`
+	EventID        string `json:"event_id,omitempty"`
+	Audio          string `json:"audio,omitempty"`
+	ItemID         string `json:"item_id,omitempty"`
+	PreviousItemID string `json:"previous_item_id,omitempty"`
+	Delta          string `json:"delta,omitempty"`
+	Transcript     string `json:"transcript,omitempty"`
+	Error          *struct {
 		Type    string `json:"type"`
 		Code    string `json:"code"`
 		Message string `json:"message"`
@@ -104,7 +107,7 @@ type AudioAppendEvent struct {
 
 // NewRealtimeTranscriber creates a new real-time transcriber
 func NewRealtimeTranscriber() (*RealtimeTranscriber, error) {
-	config := state.Get().Config
+	config := state.Get().GetConfig()
 	if config.LLM.Keys.OpenAIKey == "" {
 		return nil, fmt.Errorf("OpenAI API key not configured")
 	}
@@ -113,11 +116,12 @@ func NewRealtimeTranscriber() (*RealtimeTranscriber, error) {
 
 	return &RealtimeTranscriber{
 		apiKey:         config.LLM.Keys.OpenAIKey,
-		transcriptChan: make(chan string, 100),
-		partialChan:    make(chan string, 100),
+		transcriptChan: make(chan TranscriptEvent, 100),
+		partialChan:    make(chan TranscriptEvent, 100),
 		errorChan:      make(chan error, 10),
 		ctx:            ctx,
 		cancel:         cancel,
+		previousItems:  make(map[string]string),
 	}, nil
 }
 
@@ -132,6 +136,7 @@ func (rt *RealtimeTranscriber) Start() error {
 
 	// Recreate context for this transcription session
 	rt.ctx, rt.cancel = context.WithCancel(context.Background())
+	rt.previousItems = make(map[string]string)
 
 	// Connect directly to WebSocket (no session creation needed)
 	if err := rt.connectWebSocket(); err != nil {
@@ -172,6 +177,11 @@ func (rt *RealtimeTranscriber) Stop() {
 	logger.Infof("🎙️ Real-time transcription stopped")
 }
 
+// Cancel closes the session; the OpenAI session has nothing to drain, so it is the same as Stop
+func (rt *RealtimeTranscriber) Cancel() {
+	rt.Stop()
+}
+
 // SendAudio sends PCM audio data to the WebSocket
 func (rt *RealtimeTranscriber) SendAudio(pcmData []byte) error {
 	rt.mu.Lock()
@@ -196,12 +206,12 @@ func (rt *RealtimeTranscriber) SetModel(model string) {
 }
 
 // TranscriptChan returns channel for complete transcripts
-func (rt *RealtimeTranscriber) TranscriptChan() <-chan string {
+func (rt *RealtimeTranscriber) TranscriptChan() <-chan TranscriptEvent {
 	return rt.transcriptChan
 }
 
-// PartialChan returns channel for partial transcripts
-func (rt *RealtimeTranscriber) PartialChan() <-chan string {
+// PartialChan returns channel for partial transcript deltas
+func (rt *RealtimeTranscriber) PartialChan() <-chan TranscriptEvent {
 	return rt.partialChan
 }
 
@@ -311,41 +321,29 @@ func (rt *RealtimeTranscriber) handleMessages() {
 // processEvent handles different types of WebSocket events
 func (rt *RealtimeTranscriber) processEvent(event WSEvent) {
 	switch event.Type {
-	case "input_audio_transcription.delta":
-		if event.Delta != "" {
-			logger.Debugf("📝 Partial transcript: %s", event.Delta)
-			select {
-			case rt.partialChan <- event.Delta:
-			default:
-				// Drop if channel is full
-			}
-		}
-	case "input_audio_transcription.completed":
-		if event.Transcript != "" {
-			logger.Debugf("📝 Complete transcript: %s", event.Transcript)
-			select {
-			case rt.transcriptChan <- event.Transcript:
-			default:
-				// Drop if channel is full
-			}
+	case "input_audio_buffer.committed":
+		// Remember ordering so segments can be assembled in speech order
+		if event.ItemID != "" && event.PreviousItemID != "" {
+			rt.previousItems[event.ItemID] = event.PreviousItemID
 		}
-	case "conversation.item.input_audio_transcription.delta":
+
+	case "input_audio_transcription.delta", "conversation.item.input_audio_transcription.delta":
 		if event.Delta != "" {
-			logger.Debugf("📝 Partial transcript: %s", event.Delta)
+			logger.Debugf("📝 Partial transcript [%s]: %s", event.ItemID, event.Delta)
 			select {
-			case rt.partialChan <- event.Delta:
+			case rt.partialChan <- rt.newTranscriptEvent(event.ItemID, event.Delta):
 			default:
-				// Drop if channel is full
+				// Drop if channel is full - the completed event carries the full text
 			}
 		}
 
-	case "conversation.item.input_audio_transcription.completed":
+	case "input_audio_transcription.completed", "conversation.item.input_audio_transcription.completed":
 		if event.Transcript != "" {
-			logger.Debugf("📝 Complete transcript: %s", event.Transcript)
+			logger.Debugf("📝 Complete transcript [%s]: %s", event.ItemID, event.Transcript)
+			// Completed segments must never be dropped
 			select {
-			case rt.transcriptChan <- event.Transcript:
-			default:
-				// Drop if channel is full
+			case rt.transcriptChan <- rt.newTranscriptEvent(event.ItemID, event.Transcript):
+			case <-rt.ctx.Done():
 			}
 		}
 
@@ -363,6 +361,15 @@ func (rt *RealtimeTranscriber) processEvent(event WSEvent) {
 	}
 }
 
+// newTranscriptEvent builds a transcript event annotated with the previous item ID
+func (rt *RealtimeTranscriber) newTranscriptEvent(itemID, text string) TranscriptEvent {
+	return TranscriptEvent{
+		ItemID:         itemID,
+		PreviousItemID: rt.previousItems[itemID],
+		Text:           text,
+	}
+}
+
 // encodeAudioToBase64 converts PCM audio data to base64 string
 func encodeAudioToBase64(pcmData []byte) string {
 	// PCM data is already in the correct format (16-bit signed integers)
@@ -0,0 +1,59 @@
+package transcriber
+
+import (
+	"fmt"
+
+	"github.com/dooshek/voicify/internal/state"
+)
+
+const (
+	// StreamingBackendOpenAI streams to the OpenAI Realtime transcription API
+	StreamingBackendOpenAI = "openai"
+	// StreamingBackendGeneric streams to a self-hosted server speaking the generic protocol
+	StreamingBackendGeneric = "generic"
+)
+
+// StreamingTranscriber is a realtime speech-to-text backend fed with
+// 24 kHz PCM16 mono audio. Partial updates carry text deltas, completed
+// updates carry the full text of a segment.
+type StreamingTranscriber interface {
+	// Start opens a new streaming session
+	Start() error
+	// SendAudio streams a chunk of PCM audio
+	SendAudio(pcmData []byte) error
+	// PartialChan returns channel for partial transcript deltas
+	PartialChan() <-chan TranscriptEvent
+	// TranscriptChan returns channel for completed segments
+	TranscriptChan() <-chan TranscriptEvent
+	// ErrorChan returns channel for errors
+	ErrorChan() <-chan error
+	// SetModel sets the transcription model for the next session
+	SetModel(model string)
+	// Stop ends the audio and closes the session once the segments still
+	// being transcribed have arrived
+	Stop()
+	// Cancel closes the session without waiting for pending segments
+	Cancel()
+}
+
+// NewStreamingTranscriber creates the streaming backend selected in config
+func NewStreamingTranscriber() (StreamingTranscriber, error) {
+	cfg := state.Get().GetConfig().LLM.Transcription.Streaming
+
+	switch cfg.Backend {
+	case "", StreamingBackendOpenAI:
+		rt, err := NewRealtimeTranscriber()
+		if err != nil {
+			return nil, err
+		}
+		return rt, nil
+	case StreamingBackendGeneric:
+		gt, err := NewGenericStreamingTranscriber(cfg.URL, cfg.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		return gt, nil
+	default:
+		return nil, fmt.Errorf("unsupported streaming backend: %s", cfg.Backend)
+	}
+}
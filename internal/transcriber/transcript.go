@@ -0,0 +1,209 @@
+package transcriber
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// TranscriptEvent is a single transcription update for one audio item
+type TranscriptEvent struct {
+	// ItemID identifies the audio item (speech segment) the update belongs to
+	ItemID string
+	// PreviousItemID is the item committed right before this one, if known
+	PreviousItemID string
+	// Text is the delta for partial updates and the full text for completed ones
+	Text string
+}
+
+// segment holds the text of a single audio item
+type segment struct {
+	text  string
+	final bool
+	// previous is the item this one follows, used to restore ordering
+	previous string
+}
+
+// Transcript assembles realtime transcription updates into ordered text.
+// Segments are keyed by item ID so deltas and completions that arrive
+// interleaved or out of order still end up in the right place.
+type Transcript struct {
+	mu       sync.Mutex
+	segments map[string]*segment
+	order    []string
+}
+
+// NewTranscript creates an empty transcript
+func NewTranscript() *Transcript {
+	return &Transcript{
+		segments: make(map[string]*segment),
+	}
+}
+
+// Reset clears all segments
+func (t *Transcript) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.segments = make(map[string]*segment)
+	t.order = nil
+}
+
+// ApplyDelta appends a partial delta to its segment and returns the segment text so far.
+// Deltas for a segment that is already final are ignored.
+func (t *Transcript) ApplyDelta(ev TranscriptEvent) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seg := t.segmentFor(ev.ItemID, ev.PreviousItemID)
+	if !seg.final {
+		seg.text += ev.Text
+	}
+	return seg.text
+}
+
+// Complete sets the final text of a segment, replacing any partial text.
+// A later completion for the same item is treated as a correction.
+func (t *Transcript) Complete(ev TranscriptEvent) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seg := t.segmentFor(ev.ItemID, ev.PreviousItemID)
+	// Keep line breaks inserted by voice commands
+	seg.text = strings.Trim(ev.Text, " \t")
+	seg.final = true
+	return seg.text
+}
+
+// DeleteLastSentence removes the last sentence from the most recent non-empty
+// segment other than the one with itemID, the segment the command was spoken
+// in. It returns the removed text and whether nothing of the segment is left.
+func (t *Transcript) DeleteLastSentence(itemID string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seg := t.lastSegment(itemID)
+	if seg == nil {
+		return "", false
+	}
+	kept := TrimLastSentence(seg.text)
+	removed := seg.text[len(kept):]
+	seg.text = kept
+	return removed, strings.TrimSpace(kept) == ""
+}
+
+// DropLastSegment clears the most recent non-empty segment other than the one
+// with itemID and returns its text
+func (t *Transcript) DropLastSegment(itemID string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seg := t.lastSegment(itemID)
+	if seg == nil {
+		return ""
+	}
+	// The emptied segment stays so items following it keep their order
+	removed := seg.text
+	seg.text = ""
+	return removed
+}
+
+// Text returns the assembled transcript in item order. Segments that never
+// completed contribute their partial text so nothing spoken is lost on stop.
+func (t *Transcript) Text() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var b strings.Builder
+	for _, id := range t.orderedIDs() {
+		text := strings.Trim(t.segments[id].text, " \t")
+		if text == "" {
+			continue
+		}
+		assembled := b.String()
+		if b.Len() > 0 && !strings.HasSuffix(assembled, "\n") && !strings.HasPrefix(text, "\n") {
+			b.WriteString(" ")
+		}
+		b.WriteString(text)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// TrimLastSentence removes the last sentence from text, keeping earlier sentences
+// and any line breaks that precede the removed one
+func TrimLastSentence(text string) string {
+	trimmed := strings.TrimRight(text, " \t")
+	body := strings.TrimRight(trimmed, ".!?…")
+
+	cut := strings.LastIndexAny(body, ".!?…\n")
+	if cut < 0 {
+		return ""
+	}
+	_, size := utf8.DecodeRuneInString(body[cut:])
+	return text[:cut+size]
+}
+
+// segmentFor returns the segment for itemID, creating it if needed.
+// Must be called with t.mu held.
+func (t *Transcript) segmentFor(itemID, previousItemID string) *segment {
+	seg, exists := t.segments[itemID]
+	if !exists {
+		seg = &segment{}
+		t.segments[itemID] = seg
+		t.order = append(t.order, itemID)
+	}
+	if previousItemID != "" {
+		seg.previous = previousItemID
+	}
+	return seg
+}
+
+// lastSegment returns the most recent non-empty segment other than the one
+// with itemID, or nil. Must be called with t.mu held.
+func (t *Transcript) lastSegment(itemID string) *segment {
+	ids := t.orderedIDs()
+	for i := len(ids) - 1; i >= 0; i-- {
+		if ids[i] == itemID || strings.TrimSpace(t.segments[ids[i]].text) == "" {
+			continue
+		}
+		return t.segments[ids[i]]
+	}
+	return nil
+}
+
+// orderedIDs returns item IDs with every item placed right after the item it
+// follows. Items whose predecessor is unknown keep their arrival order.
+// Must be called with t.mu held.
+func (t *Transcript) orderedIDs() []string {
+	followers := make(map[string][]string)
+	var roots []string
+	for _, id := range t.order {
+		prev := t.segments[id].previous
+		if _, known := t.segments[prev]; prev != "" && known && prev != id {
+			followers[prev] = append(followers[prev], id)
+		} else {
+			roots = append(roots, id)
+		}
+	}
+
+	ordered := make([]string, 0, len(t.order))
+	visited := make(map[string]bool)
+	var visit func(id string)
+	visit = func(id string) {
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+		ordered = append(ordered, id)
+		for _, next := range followers[id] {
+			visit(next)
+		}
+	}
+	for _, id := range roots {
+		visit(id)
+	}
+	// Items caught in a cycle of previous links are appended in arrival order
+	for _, id := range t.order {
+		visit(id)
+	}
+	return ordered
+}
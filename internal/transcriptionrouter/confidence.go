@@ -0,0 +1,160 @@
+package transcriptionrouter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/internal/types"
+)
+
+const (
+	// defaultMinConfidence is used when llm.router.min_confidence is not set
+	defaultMinConfidence = 0.6
+	// maxCandidates limits the actions offered when routing is ambiguous
+	maxCandidates = 3
+
+	lowConfidencePaste = "paste"
+	lowConfidenceAsk   = "ask"
+
+	// defaultActionName pastes the transcription
+	defaultActionName = "default"
+)
+
+// ErrNoPendingChoice is returned when a candidate is chosen but no routing is ambiguous
+var ErrNoPendingChoice = errors.New("no ambiguous routing is waiting for a choice")
+
+// Candidate is an action the LLM considered, with its confidence
+type Candidate struct {
+	Action     string  `json:"action"`
+	Confidence float64 `json:"confidence"`
+}
+
+// AmbiguityNotifier lets the user choose an action when the LLM is unsure.
+// It is implemented by the D-Bus server.
+type AmbiguityNotifier interface {
+	EmitRoutingAmbiguous(transcription string, candidates []Candidate) error
+}
+
+// pendingChoice is an ambiguous transcription waiting for the user's choice
+type pendingChoice struct {
+	transcription string
+	text          string // transcription without the command
+	typed         bool   // transcription is already in the focused window
+}
+
+// handleLowConfidence does not run the LLM's guess. It pastes the
+// transcription, or with low_confidence: ask, lets the user pick an action.
+// transcription is the utterance as said, with any command prefix.
+func (r *Router) handleLowConfidence(ec *types.ExecutionContext, transcription string, resp *llmResponse) error {
+	if r.lowConfidence == lowConfidenceAsk {
+		if notifier, ok := state.Get().GetDBusServer().(AmbiguityNotifier); ok {
+			candidates := r.candidates(resp)
+			r.mu.Lock()
+			r.pending = &pendingChoice{transcription: transcription, text: resp.TranscriptionWithoutCommand, typed: r.isTyped(ec.RequestID)}
+			r.mu.Unlock()
+
+			logger.Infof("Router: Confidence %s is below %.2f - asking the user to choose between %v",
+				formatConfidence(resp.Confidence), r.minConfidence, candidates)
+			return notifier.EmitRoutingAmbiguous(transcription, candidates)
+		}
+		logger.Info("Router: Cannot ask for a choice without the D-Bus server")
+	}
+
+	logger.Infof("Router: Confidence %s is below %.2f - pasting the transcription instead of running %s",
+		formatConfidence(resp.Confidence), r.minConfidence, resp.Action)
+	return r.pasteFallback(ec, transcription)
+}
+
+// ChooseCandidate runs the action the user picked for the last ambiguous
+// transcription. "default" pastes the transcription.
+func (r *Router) ChooseCandidate(actionName string) error {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	if pending == nil {
+		return ErrNoPendingChoice
+	}
+
+	ec, finish := r.newExecution(pending.transcription, pending.typed)
+	defer finish()
+	if actionName == defaultActionName {
+		logger.Info("Router: User chose to paste the transcription")
+		return r.pasteFallback(ec, pending.transcription)
+	}
+
+	action := r.findAction(actionName)
+	if action == nil {
+		return fmt.Errorf("unknown action: %s", actionName)
+	}
+	logger.Infof("Router: User chose action %s", actionName)
+	result, err := r.execute(ec, action, pending.text, nil)
+	if err != nil {
+		return err
+	}
+	r.deliverText(ec, result)
+	r.remember(pending.text, actionName)
+	return nil
+}
+
+// pasteFallback pastes the transcription with the default action
+func (r *Router) pasteFallback(ec *types.ExecutionContext, transcription string) error {
+	action := r.findAction(defaultActionName)
+	if action == nil {
+		return fmt.Errorf("default action not available")
+	}
+	result, err := r.execute(ec, action, transcription, nil)
+	if err != nil {
+		return err
+	}
+	r.deliverText(ec, result)
+	r.remember(transcription, defaultActionName)
+	return nil
+}
+
+// candidates returns the chosen action and the LLM's alternatives, most
+// confident first. Unknown actions and duplicates are dropped.
+func (r *Router) candidates(resp *llmResponse) []Candidate {
+	all := append([]Candidate{{Action: resp.Action, Confidence: confidenceValue(resp.Confidence)}}, resp.Alternatives...)
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Confidence > all[j].Confidence
+	})
+
+	seen := make(map[string]bool)
+	var candidates []Candidate
+	for _, c := range all {
+		if seen[c.Action] || r.findAction(c.Action) == nil {
+			continue
+		}
+		seen[c.Action] = true
+		candidates = append(candidates, c)
+		if len(candidates) == maxCandidates {
+			break
+		}
+	}
+	return candidates
+}
+
+// belowThreshold reports whether the LLM is not confident enough to run its
+// choice. A missing confidence counts as below the threshold.
+func (r *Router) belowThreshold(confidence *float64) bool {
+	return confidence == nil || *confidence < r.minConfidence
+}
+
+func confidenceValue(confidence *float64) float64 {
+	if confidence == nil {
+		return 0
+	}
+	return *confidence
+}
+
+func formatConfidence(confidence *float64) string {
+	if confidence == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.2f", *confidence)
+}
@@ -0,0 +1,80 @@
+package transcriptionrouter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/state"
+)
+
+// defaultHistorySize is used when llm.router.history_size is not set
+const defaultHistorySize = 5
+
+// RecentRoute is an utterance the router handled and the action it chose
+type RecentRoute struct {
+	Text   string
+	Action string
+	Time   time.Time
+}
+
+// PromptData holds the variables available in the router prompt template
+type PromptData struct {
+	// Actions lists the LLM-routed actions, one "- ..." line each
+	Actions       string
+	Transcription string
+	// WindowTitle and WindowApp describe the focused window, as reported by the GNOME extension
+	WindowTitle string
+	WindowApp   string
+	// RecordingMode is how the recording was started: keyboard, auto_paste, router, realtime or hybrid
+	RecordingMode string
+	// Recent are the last routed utterances, oldest first
+	Recent []RecentRoute
+}
+
+// promptData collects the template variables for the transcription
+func (r *Router) promptData(transcription string) PromptData {
+	actionsDoc := strings.Builder{}
+
+	logger.Debugf("Building LLM actions documentation with %d available actions", len(r.actions))
+	for _, a := range r.actions {
+		if meta := a.GetMetadata(); isLLMAction(meta) {
+			logger.Debugf("Adding action to LLM prompt: %s", meta.Name)
+			actionsDoc.WriteString("- " + *meta.LLMRouterPrompt + "\n")
+			if meta.Parameters != nil {
+				actionsDoc.WriteString("  Parameters (JSON Schema): " + describeParameters(meta.Parameters) + "\n")
+			}
+		}
+	}
+
+	title, app := state.Get().GetFocusedWindow()
+	return PromptData{
+		Actions:       actionsDoc.String(),
+		Transcription: transcription,
+		WindowTitle:   title,
+		WindowApp:     app,
+		RecordingMode: string(state.Get().GetRecordingMode()),
+		Recent:        r.recentRoutes(),
+	}
+}
+
+// remember adds a routed utterance to the history shown to the LLM
+func (r *Router) remember(text, action string) {
+	if r.historySize <= 0 || text == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recent = append(r.recent, RecentRoute{Text: text, Action: action, Time: time.Now()})
+	if len(r.recent) > r.historySize {
+		r.recent = r.recent[len(r.recent)-r.historySize:]
+	}
+}
+
+// recentRoutes returns a copy of the routing history, oldest first
+func (r *Router) recentRoutes() []RecentRoute {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecentRoute(nil), r.recent...)
+}
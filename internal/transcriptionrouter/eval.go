@@ -0,0 +1,318 @@
+package transcriptionrouter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	llm "github.com/dooshek/voicify/internal/llm"
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/state"
+	"gopkg.in/yaml.v3"
+)
+
+// evalErrorLabel is the confusion matrix column of cases the LLM call failed for
+const evalErrorLabel = "(error)"
+
+// EvalCase is a labeled utterance of a router evaluation dataset
+type EvalCase struct {
+	Utterance      string `yaml:"utterance" json:"utterance"`
+	ExpectedAction string `yaml:"expected_action" json:"expected_action"`
+	// ExpectedText is the transcription without the command; nil skips the check
+	ExpectedText *string `yaml:"expected_text" json:"expected_text"`
+	// WindowTitle and WindowApp describe the focused window the utterance was spoken in
+	WindowTitle string `yaml:"window_title" json:"window_title"`
+	WindowApp   string `yaml:"window_app" json:"window_app"`
+	// FakeResponse is the raw LLM answer returned by NewEvalFakeProvider
+	FakeResponse string `yaml:"fake_response" json:"fake_response"`
+}
+
+// EvalResult is the router's answer for one case
+type EvalResult struct {
+	Case       EvalCase
+	Action     string
+	Text       string
+	Confidence *float64
+	Latency    time.Duration
+	Usage      llm.Usage
+	// Err is set when the LLM call or its response failed
+	Err error
+}
+
+// ActionCorrect reports whether the LLM chose the expected action
+func (res EvalResult) ActionCorrect() bool {
+	return res.Err == nil && strings.EqualFold(res.Action, res.Case.ExpectedAction)
+}
+
+// TextCorrect reports whether the text matches the expected one, ignoring
+// case, surrounding punctuation and repeated spaces
+func (res EvalResult) TextCorrect() bool {
+	return res.Err == nil && res.Case.ExpectedText != nil &&
+		normalizeEvalText(res.Text) == normalizeEvalText(*res.Case.ExpectedText)
+}
+
+// EvalReport holds the results of an evaluation run
+type EvalReport struct {
+	Provider string
+	Model    string
+	Results  []EvalResult
+}
+
+// EvalOptions configures an evaluation run
+type EvalOptions struct {
+	// Provider replaces the configured router provider, e.g. with NewEvalFakeProvider
+	Provider llm.Provider
+	// ProviderName names Provider in the report
+	ProviderName string
+	// OnResult is called after each case, e.g. to show progress
+	OnResult func(index int, result EvalResult)
+}
+
+// Evaluate asks the router LLM about every case and compares its answers
+// with the labels. No action is executed. Cases run one after another, so
+// latencies are comparable and the provider is called once per case, in order.
+func Evaluate(ctx context.Context, cases []EvalCase, opts EvalOptions) (*EvalReport, error) {
+	r, err := createNewRouter()
+	if err != nil {
+		logger.Warnf("Router: Evaluating with an incomplete router: %v", err)
+	}
+	defer r.baseCancel()
+
+	cfg := state.Get().GetConfig().LLM.Router
+	report := &EvalReport{Provider: cfg.Provider, Model: cfg.Model}
+	if opts.Provider != nil {
+		r.llmProvider = opts.Provider
+		report.Provider = opts.ProviderName
+		if r.prompt == nil {
+			if err := r.cachePromptTemplate(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if reason := r.llmSkipReason(); reason != "" {
+		return nil, fmt.Errorf("cannot evaluate the router: %s", reason)
+	}
+
+	metered := &meteredProvider{Provider: r.llmProvider}
+	r.llmProvider = metered
+	for i, c := range cases {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		state.Get().SetFocusedWindow(c.WindowTitle, c.WindowApp)
+
+		start := time.Now()
+		resp, err := r.analyzeWithLLM(ctx, c.Utterance)
+		res := EvalResult{Case: c, Latency: time.Since(start), Usage: metered.last, Err: err}
+		if err == nil {
+			res.Action = resp.Action
+			res.Text = resp.TranscriptionWithoutCommand
+			res.Confidence = resp.Confidence
+		}
+
+		report.Results = append(report.Results, res)
+		if opts.OnResult != nil {
+			opts.OnResult(i, res)
+		}
+	}
+	return report, nil
+}
+
+// Accuracy is the share of cases with the expected action
+func (rep *EvalReport) Accuracy() float64 {
+	correct := 0
+	for _, res := range rep.Results {
+		if res.ActionCorrect() {
+			correct++
+		}
+	}
+	return ratio(correct, len(rep.Results))
+}
+
+// TextAccuracy is the share of cases with an expected text that got it,
+// together with the number of such cases
+func (rep *EvalReport) TextAccuracy() (float64, int) {
+	correct, checked := 0, 0
+	for _, res := range rep.Results {
+		if res.Case.ExpectedText == nil {
+			continue
+		}
+		checked++
+		if res.TextCorrect() {
+			correct++
+		}
+	}
+	return ratio(correct, checked), checked
+}
+
+// Confusion counts the chosen actions per expected action. Labels are the
+// actions seen in either role, sorted; failed calls are counted as "(error)".
+func (rep *EvalReport) Confusion() ([]string, map[string]map[string]int) {
+	counts := make(map[string]map[string]int)
+	seen := make(map[string]bool)
+	for _, res := range rep.Results {
+		expected := strings.ToLower(res.Case.ExpectedAction)
+		actual := strings.ToLower(res.Action)
+		if res.Err != nil {
+			actual = evalErrorLabel
+		}
+		if counts[expected] == nil {
+			counts[expected] = make(map[string]int)
+		}
+		counts[expected][actual]++
+		seen[expected] = true
+		seen[actual] = true
+	}
+
+	labels := make([]string, 0, len(seen))
+	for label := range seen {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels, counts
+}
+
+// Latency returns the mean, median, 95th percentile and maximum latency
+func (rep *EvalReport) Latency() (mean, p50, p95, slowest time.Duration) {
+	if len(rep.Results) == 0 {
+		return 0, 0, 0, 0
+	}
+	latencies := make([]time.Duration, len(rep.Results))
+	var total time.Duration
+	for i, res := range rep.Results {
+		latencies[i] = res.Latency
+		total += res.Latency
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	percentile := func(p float64) time.Duration {
+		return latencies[int(p*float64(len(latencies)-1)+0.5)]
+	}
+	return total / time.Duration(len(latencies)), percentile(0.5), percentile(0.95), latencies[len(latencies)-1]
+}
+
+// Usage sums the tokens of all cases
+func (rep *EvalReport) Usage() llm.Usage {
+	var total llm.Usage
+	for _, res := range rep.Results {
+		total.PromptTokens += res.Usage.PromptTokens
+		total.CompletionTokens += res.Usage.CompletionTokens
+	}
+	return total
+}
+
+// Cost prices the token usage given USD per million input and output tokens
+func (rep *EvalReport) Cost(inputPrice, outputPrice float64) float64 {
+	usage := rep.Usage()
+	return (float64(usage.PromptTokens)*inputPrice + float64(usage.CompletionTokens)*outputPrice) / 1e6
+}
+
+// LoadEvalCases reads a dataset: a YAML list of cases (.yaml, .yml) or one
+// JSON case per line (.jsonl, blank lines and # comments skipped)
+func LoadEvalCases(path string) ([]EvalCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+
+	var cases []EvalCase
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &cases); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	case ".jsonl":
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+			var c EvalCase
+			if err := json.Unmarshal([]byte(text), &c); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+			cases = append(cases, c)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported dataset format %q, use .yaml, .yml or .jsonl", ext)
+	}
+
+	for i, c := range cases {
+		if strings.TrimSpace(c.Utterance) == "" || c.ExpectedAction == "" {
+			return nil, fmt.Errorf("case %d needs utterance and expected_action", i+1)
+		}
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("dataset %s has no cases", path)
+	}
+	return cases, nil
+}
+
+// NewEvalFakeProvider returns a provider answering the cases in order, as
+// Evaluate asks about them: with a case's FakeResponse, or when it has none,
+// with its expected action and text. It tests datasets and prompt templates
+// without API calls.
+func NewEvalFakeProvider(cases []EvalCase) *llm.FakeProvider {
+	next := 0
+	return &llm.FakeProvider{Respond: func(req llm.CompletionRequest) (string, error) {
+		if next >= len(cases) {
+			return "", fmt.Errorf("fake provider has no answer for request %d", next+1)
+		}
+		c := cases[next]
+		next++
+		if c.FakeResponse != "" {
+			return c.FakeResponse, nil
+		}
+
+		text := c.Utterance
+		if c.ExpectedText != nil {
+			text = *c.ExpectedText
+		}
+		confidence := 1.0
+		data, err := json.Marshal(llmResponse{
+			Thoughts:                    "fake provider answer",
+			Action:                      c.ExpectedAction,
+			Confidence:                  &confidence,
+			TranscriptionWithoutCommand: text,
+		})
+		return string(data), err
+	}}
+}
+
+// meteredProvider remembers the token usage of the last completion
+type meteredProvider struct {
+	llm.Provider
+	last llm.Usage
+}
+
+func (p *meteredProvider) Completion(ctx context.Context, req llm.CompletionRequest) (string, error) {
+	response, usage, err := llm.CompleteWithUsage(ctx, p.Provider, req)
+	p.last = usage
+	return response, err
+}
+
+// normalizeEvalText makes texts comparable regardless of case, surrounding
+// punctuation and whitespace
+func normalizeEvalText(text string) string {
+	text = strings.Trim(strings.ToLower(text), " \t\n.,!?;:\"'")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
@@ -0,0 +1,134 @@
+package transcriptionrouter
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/internal/types"
+)
+
+func TestMain(m *testing.M) {
+	state.Init(&types.Config{})
+	os.Exit(m.Run())
+}
+
+// wrongAnswer is the fake LLM answer of the misrouted case
+const wrongAnswer = `{"action": "vscode", "transcription_without_command": "close the tab"}`
+
+func TestEvaluateWithFakeProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		dataset string
+	}{
+		{
+			name: "yaml",
+			file: "cases.yaml",
+			dataset: `- utterance: open the browser
+  expected_action: browser
+- utterance: paste hello world
+  expected_action: default
+  expected_text: hello world
+- utterance: close the tab
+  expected_action: browser
+  fake_response: '` + wrongAnswer + `'
+- utterance: what time is it
+  expected_action: default
+  fake_response: not json
+`,
+		},
+		{
+			name: "jsonl",
+			file: "cases.jsonl",
+			dataset: `# the same cases, one per line
+{"utterance": "open the browser", "expected_action": "browser"}
+{"utterance": "paste hello world", "expected_action": "default", "expected_text": "hello world"}
+
+{"utterance": "close the tab", "expected_action": "browser", "fake_response": ` + strconv.Quote(wrongAnswer) + `}
+{"utterance": "what time is it", "expected_action": "default", "fake_response": "not json"}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.dataset), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cases, err := LoadEvalCases(path)
+			if err != nil {
+				t.Fatalf("LoadEvalCases: %v", err)
+			}
+
+			var seen []int
+			report, err := Evaluate(context.Background(), cases, EvalOptions{
+				Provider:     NewEvalFakeProvider(cases),
+				ProviderName: "fake",
+				OnResult:     func(index int, _ EvalResult) { seen = append(seen, index) },
+			})
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+
+			if report.Provider != "fake" {
+				t.Errorf("provider = %q, want fake", report.Provider)
+			}
+			if want := []int{0, 1, 2, 3}; !reflect.DeepEqual(seen, want) {
+				t.Errorf("OnResult indexes = %v, want %v", seen, want)
+			}
+			if got := report.Accuracy(); got != 0.5 {
+				t.Errorf("accuracy = %v, want 0.5", got)
+			}
+			if got, checked := report.TextAccuracy(); got != 1 || checked != 1 {
+				t.Errorf("text accuracy = %v of %d, want 1 of 1", got, checked)
+			}
+			if report.Results[3].Err == nil {
+				t.Error("malformed answer did not fail its case")
+			}
+
+			labels, counts := report.Confusion()
+			if want := []string{evalErrorLabel, "browser", "default", "vscode"}; !reflect.DeepEqual(labels, want) {
+				t.Errorf("confusion labels = %v, want %v", labels, want)
+			}
+			wantCounts := map[string]map[string]int{
+				"browser": {"browser": 1, "vscode": 1},
+				"default": {"default": 1, evalErrorLabel: 1},
+			}
+			if !reflect.DeepEqual(counts, wantCounts) {
+				t.Errorf("confusion = %v, want %v", counts, wantCounts)
+			}
+
+			// The fake provider estimates four characters per token
+			if got, want := report.Results[2].Usage.CompletionTokens, len(wrongAnswer)/4; got != want {
+				t.Errorf("completion tokens of the misrouted case = %d, want %d", got, want)
+			}
+			// A malformed answer still used its tokens
+			if got, want := report.Results[3].Usage.CompletionTokens, len("not json")/4; got != want {
+				t.Errorf("completion tokens of the failed case = %d, want %d", got, want)
+			}
+			usage := report.Usage()
+			prompt, completion := 0, 0
+			for _, res := range report.Results {
+				if res.Usage.PromptTokens == 0 {
+					t.Errorf("case %q has no prompt tokens", res.Case.Utterance)
+				}
+				prompt += res.Usage.PromptTokens
+				completion += res.Usage.CompletionTokens
+			}
+			if usage.PromptTokens != prompt || usage.CompletionTokens != completion {
+				t.Errorf("usage = %+v, want %d prompt and %d completion tokens", usage, prompt, completion)
+			}
+			wantCost := (float64(prompt)*0.15 + float64(completion)*0.6) / 1e6
+			if got := report.Cost(0.15, 0.6); math.Abs(got-wantCost) > 1e-12 {
+				t.Errorf("cost = %v, want %v", got, wantCost)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package transcriptionrouter
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/plugin"
+	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/internal/types"
+	"github.com/dooshek/voicify/pkg/pluginapi"
+)
+
+// defaultActionTimeout is used when llm.router.action_timeout is not set
+const defaultActionTimeout = 2 * time.Minute
+
+// Report aggregates the results of the actions one routing request ran
+type Report struct {
+	RequestID     string               `json:"request_id"`
+	Transcription string               `json:"transcription"`
+	Results       []types.ActionResult `json:"results"`
+}
+
+// ResultNotifier tells the user what the actions of a request did.
+// It is implemented by the D-Bus server.
+type ResultNotifier interface {
+	EmitActionCompleted(report Report) error
+}
+
+// request is a routing request in flight
+type request struct {
+	cancel context.CancelFunc
+	report Report
+	// typed is set when the transcription is already in the focused window
+	typed bool
+}
+
+// requestSet tracks the routing requests in flight. A reloaded router shares
+// it with its predecessor, so Cancel also reaches requests started before the
+// reload.
+type requestSet struct {
+	byID map[string]*request
+	mu   sync.Mutex
+}
+
+func newRequestSet() *requestSet {
+	return &requestSet{byID: make(map[string]*request)}
+}
+
+// newExecution starts a routing request. Its context is cancelled by Cancel,
+// Shutdown or the returned finish function, which also reports the results.
+// With typed the default action does not paste the transcription again.
+func (r *Router) newExecution(transcription string, typed bool) (*types.ExecutionContext, func()) {
+	ctx, cancel := context.WithCancel(r.baseCtx)
+	title, app := state.Get().GetFocusedWindow()
+	ec := &types.ExecutionContext{
+		Context:       ctx,
+		RequestID:     newRequestID(),
+		RecordingMode: state.Get().GetRecordingMode(),
+		Language:      state.Get().GetConfig().LLM.Transcription.Language,
+		WindowTitle:   title,
+		WindowApp:     app,
+	}
+
+	req := &request{cancel: cancel, report: Report{RequestID: ec.RequestID, Transcription: transcription}, typed: typed}
+	r.inflight.mu.Lock()
+	r.inflight.byID[ec.RequestID] = req
+	r.inflight.mu.Unlock()
+
+	return ec, func() {
+		r.inflight.mu.Lock()
+		delete(r.inflight.byID, ec.RequestID)
+		r.inflight.mu.Unlock()
+		cancel()
+		r.report(req.report)
+	}
+}
+
+// report sends the results of a finished request to the user
+func (r *Router) report(report Report) {
+	if len(report.Results) == 0 {
+		return
+	}
+	notifier, ok := state.Get().GetDBusServer().(ResultNotifier)
+	if !ok {
+		for _, res := range report.Results {
+			logger.Debugf("Router: [%s] %s (%s): %s %s", report.RequestID, res.Action, res.Severity, res.Message, res.URL)
+		}
+		return
+	}
+	if err := notifier.EmitActionCompleted(report); err != nil {
+		logger.Warnf("Router: [%s] Failed to report action results: %v", report.RequestID, err)
+	}
+}
+
+// isTyped reports whether the request's transcription is already in the focused window
+func (r *Router) isTyped(requestID string) bool {
+	r.inflight.mu.Lock()
+	defer r.inflight.mu.Unlock()
+	req, ok := r.inflight.byID[requestID]
+	return ok && req.typed
+}
+
+// record adds an action result to its request's report
+func (r *Router) record(requestID string, result types.ActionResult) {
+	r.inflight.mu.Lock()
+	defer r.inflight.mu.Unlock()
+	if req, ok := r.inflight.byID[requestID]; ok {
+		req.report.Results = append(req.report.Results, result)
+	}
+}
+
+// deliverText pastes the text an action produced into the focused window
+func (r *Router) deliverText(ec *types.ExecutionContext, result *types.ActionResult) {
+	if result == nil || result.Text == "" {
+		return
+	}
+	logger.Debugf("Router: [%s] Pasting the output of %s", ec.RequestID, result.Action)
+	if err := plugin.RequestPaste(result.Text); err != nil {
+		logger.Errorf("Failed to paste the output of %s", err, result.Action)
+	}
+}
+
+// Cancel aborts the routing requests in flight, e.g. when the user cancels
+// the recording. It returns the number of cancelled requests.
+func (r *Router) Cancel() int {
+	r.inflight.mu.Lock()
+	defer r.inflight.mu.Unlock()
+	for id, req := range r.inflight.byID {
+		logger.Infof("Router: [%s] Cancelling routing request", id)
+		req.cancel()
+	}
+	return len(r.inflight.byID)
+}
+
+// Shutdown cancels the requests in flight and every later one
+func (r *Router) Shutdown() {
+	logger.Debug("Router: Shutting down")
+	r.baseCancel()
+}
+
+// CancelRouting aborts the global router's requests in flight, if the
+// router has been created
+func CancelRouting() int {
+	if router, ok := state.Get().GetRouter().(*Router); ok {
+		return router.Cancel()
+	}
+	return 0
+}
+
+// ShutdownRouting stops the global router's current and future requests
+func ShutdownRouting() {
+	if router, ok := state.Get().GetRouter().(*Router); ok {
+		router.Shutdown()
+	}
+}
+
+// execute runs an action with its own timeout and records its result in the
+// request's report. Actions implementing ContextAction are expected to stop
+// when the context is done; for the others the router stops waiting and they
+// finish in the background. The returned result is never nil.
+func (r *Router) execute(ec *types.ExecutionContext, action types.PluginAction, text string, rawArgs map[string]interface{}) (*types.ActionResult, error) {
+	meta := action.GetMetadata()
+	if err := ec.Context.Err(); err != nil {
+		return &types.ActionResult{Action: meta.Name}, err
+	}
+	if meta.Name == defaultActionName && r.isTyped(ec.RequestID) {
+		logger.Debugf("Router: [%s] Not pasting - the transcription was typed live", ec.RequestID)
+		return &types.ActionResult{Action: meta.Name}, nil
+	}
+
+	result, err := r.wait(ec, action, text, rawArgs)
+	if result == nil {
+		result = &types.ActionResult{}
+	}
+	result.Action = meta.Name
+	if result.Severity == "" {
+		result.Severity = types.SeverityInfo
+	}
+	if errors.Is(err, pluginapi.ErrActionSkipped) {
+		return result, err
+	}
+	if err != nil {
+		result.Severity = types.SeverityError
+		if result.Message == "" {
+			result.Message = err.Error()
+		}
+	}
+	r.record(ec.RequestID, *result)
+	return result, err
+}
+
+// wait runs the action and waits until it returns or its context is done
+func (r *Router) wait(ec *types.ExecutionContext, action types.PluginAction, text string, rawArgs map[string]interface{}) (*types.ActionResult, error) {
+	meta := action.GetMetadata()
+
+	actionEC := *ec
+	if timeout := r.timeoutFor(meta.Name); timeout > 0 {
+		var cancel context.CancelFunc
+		actionEC.Context, cancel = context.WithTimeout(ec.Context, timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		result *types.ActionResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := r.invoke(&actionEC, action, text, rawArgs)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-actionEC.Context.Done():
+		err := actionEC.Context.Err()
+		if err == context.DeadlineExceeded {
+			err = fmt.Errorf("action %s timed out after %s", meta.Name, r.timeoutFor(meta.Name))
+		}
+		logger.Warnf("Router: [%s] Stopped waiting for action %s: %v", ec.RequestID, meta.Name, err)
+		return nil, err
+	}
+}
+
+// invoke calls the richest execution method the action supports. Actions
+// declaring Parameters get validated arguments; invalid ones fall back to
+// the plain text.
+func (r *Router) invoke(ec *types.ExecutionContext, action types.PluginAction, text string, rawArgs map[string]interface{}) (*types.ActionResult, error) {
+	meta := action.GetMetadata()
+	if meta.Parameters != nil {
+		args, err := validateArgs(meta.Parameters, rawArgs)
+		if err != nil {
+			logger.Warnf("Router: [%s] Invalid arguments for %s, passing only the text: %v", ec.RequestID, meta.Name, err)
+		} else {
+			logger.Debugf("Router: [%s] Executing %s with arguments: %v", ec.RequestID, meta.Name, args)
+			ec.Args = args
+		}
+	}
+
+	switch a := action.(type) {
+	case types.ContextAction:
+		return a.ExecuteContext(ec, text)
+	case types.ArgsAction:
+		if ec.Args != nil {
+			return nil, a.ExecuteWithArgs(text, ec.Args)
+		}
+	}
+	if outputAction, ok := action.(types.OutputAction); ok {
+		output, err := outputAction.ExecuteWithOutput(text)
+		return &types.ActionResult{Text: output}, err
+	}
+	return nil, action.Execute(text)
+}
+
+// timeoutFor returns the timeout of the named action; 0 means none
+func (r *Router) timeoutFor(actionName string) time.Duration {
+	seconds, ok := r.actionTimeouts[actionName]
+	if !ok {
+		seconds = r.actionTimeout
+	}
+	switch {
+	case seconds < 0:
+		return 0
+	case seconds == 0:
+		return defaultActionTimeout
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// newRequestID returns a short random ID for log correlation
+func newRequestID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%08x", time.Now().UnixNano()&0xffffffff)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,166 @@
+package transcriptionrouter
+
+import (
+	"fmt"
+
+	"github.com/dooshek/voicify/internal/types"
+)
+
+// Explanation describes how Route would handle a transcription without
+// running any action
+type Explanation struct {
+	Transcription string
+	// Command is true when the transcription started with a command prefix
+	Command bool
+	// Text is the transcription without the command prefix
+	Text string
+
+	// Rule is the routing rule that matched, if any. No other step runs then.
+	Rule *RuleExplanation
+	// Steps are the non-LLM actions in the order Route runs them. Route stops
+	// at the first action with SkipDefaultAction that handles the text.
+	Steps []StepExplanation
+
+	// LLMSkipped explains why the LLM would not be consulted
+	LLMSkipped string
+	// Prompt is the prompt sent to the LLM
+	Prompt string
+	// PromptSource is the template file the prompt was rendered from
+	PromptSource string
+	// Decision is the LLM's answer; nil when the LLM was not consulted or failed
+	Decision *Decision
+	// LLMError is set when the LLM call or its response failed
+	LLMError error
+
+	// response is the LLM's answer Execute runs
+	response *llmResponse
+}
+
+// RuleExplanation is a routing rule that matched the transcription
+type RuleExplanation struct {
+	Name   string
+	Action string
+	Text   string
+}
+
+// StepExplanation is a non-LLM action Route tries before the LLM
+type StepExplanation struct {
+	Action            string
+	Priority          int
+	SkipDefaultAction bool
+	// Skipped explains why Route does not run the action
+	Skipped string
+	// EndsRouting is set when the action reports it handles the text, so
+	// Route stops after it
+	EndsRouting bool
+}
+
+// Decision is the action the LLM chose
+type Decision struct {
+	Action       string
+	Confidence   *float64
+	Thoughts     string
+	Text         string
+	Alternatives []Candidate
+	// Arguments are the validated arguments for actions declaring Parameters
+	Arguments types.Args
+	// ArgumentsError is set when the arguments fail validation; Route then
+	// passes only the text
+	ArgumentsError error
+	// Steps are set when Route would run several actions in a row
+	Steps []PlanStep
+	// PlanError is set when Route would reject the plan before running it
+	PlanError error
+	// UnknownAction is true when no action has the chosen name
+	UnknownAction bool
+	// BelowThreshold is true when Route would not run the action because
+	// the confidence is missing or below llm.router.min_confidence
+	BelowThreshold bool
+	MinConfidence  float64
+	// LowConfidence is what Route does then: "paste" or "ask"
+	LowConfidence string
+}
+
+// Explain shows how Route would handle the transcription. It asks the LLM
+// for its decision but never executes an action, so it is safe for dry runs.
+func (r *Router) Explain(transcription string) *Explanation {
+	text, isCommand := stripCommandPrefix(r.commandPrefixes, transcription)
+	e := &Explanation{Transcription: transcription, Command: isCommand, Text: text}
+
+	if match := matchRules(r.rules, text); match != nil {
+		e.Rule = &RuleExplanation{Name: match.rule.name, Action: match.rule.action, Text: match.text}
+		return e
+	}
+
+	endedBy := ""
+	if !isCommand {
+		for _, st := range r.nonLLMSteps() {
+			meta := st.action.GetMetadata()
+			step := StepExplanation{
+				Action:            meta.Name,
+				Priority:          meta.Priority,
+				SkipDefaultAction: meta.SkipDefaultAction,
+				Skipped:           st.skipReason,
+			}
+			switch {
+			case endedBy != "":
+				step.Skipped = "routing ends at " + endedBy
+			case st.skipReason == "" && meta.SkipDefaultAction:
+				if matcher, ok := st.action.(types.MatchAction); ok {
+					if handles, known := matcher.Handles(text); known && handles {
+						step.EndsRouting = true
+						endedBy = meta.Name
+					}
+				}
+			}
+			e.Steps = append(e.Steps, step)
+		}
+	}
+
+	if endedBy != "" {
+		e.LLMSkipped = fmt.Sprintf("unreachable, routing ends at %s", endedBy)
+		return e
+	}
+	if e.LLMSkipped = r.llmSkipReason(); e.LLMSkipped != "" {
+		return e
+	}
+
+	e.PromptSource = r.promptSource
+	if e.Prompt, e.LLMError = r.renderPrompt(text); e.LLMError != nil {
+		return e
+	}
+	resp, err := r.analyzeWithLLM(r.baseCtx, text)
+	if err != nil {
+		e.LLMError = err
+		return e
+	}
+	e.response = resp
+
+	lowConfidence := r.lowConfidence
+	if lowConfidence == "" {
+		lowConfidence = lowConfidencePaste
+	}
+	d := &Decision{
+		Action:         resp.Action,
+		Confidence:     resp.Confidence,
+		Thoughts:       resp.Thoughts,
+		Text:           resp.TranscriptionWithoutCommand,
+		Alternatives:   resp.Alternatives,
+		Steps:          resp.Steps,
+		UnknownAction:  r.findAction(resp.Action) == nil,
+		BelowThreshold: r.belowThreshold(resp.Confidence),
+		MinConfidence:  r.minConfidence,
+		LowConfidence:  lowConfidence,
+	}
+	if len(resp.Steps) > 1 {
+		// Plans do not depend on the top-level action
+		d.UnknownAction = false
+		_, d.PlanError = r.planActions(resp.Steps)
+	} else if action := r.findAction(resp.Action); action != nil {
+		if params := action.GetMetadata().Parameters; params != nil {
+			d.Arguments, d.ArgumentsError = validateArgs(params, resp.Arguments)
+		}
+	}
+	e.Decision = d
+	return e
+}
@@ -0,0 +1,130 @@
+package transcriptionrouter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/types"
+)
+
+// validateArgs checks the arguments the LLM extracted against the action's
+// schema and converts them to their declared types. Properties the schema
+// does not know and empty optional values are dropped.
+func validateArgs(schema *types.ParameterSchema, raw map[string]interface{}) (types.Args, error) {
+	return validateObject(schema, raw, "arguments")
+}
+
+func validateObject(schema *types.ParameterSchema, raw map[string]interface{}, path string) (types.Args, error) {
+	args := make(types.Args)
+	for name, value := range raw {
+		prop, ok := schema.Properties[name]
+		if !ok {
+			logger.Debugf("Router: Dropping unknown argument %s.%s", path, name)
+			continue
+		}
+		if value == nil || value == "" {
+			continue
+		}
+		converted, err := validateValue(prop, value, path+"."+name)
+		if err != nil {
+			return nil, err
+		}
+		args[name] = converted
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := args[name]; !ok {
+			return nil, fmt.Errorf("%s.%s is required", path, name)
+		}
+	}
+	return args, nil
+}
+
+func validateValue(schema *types.ParameterSchema, value interface{}, path string) (interface{}, error) {
+	var converted interface{}
+	switch schema.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, typeError(path, schema.Type, value)
+		}
+		converted = strings.TrimSpace(s)
+	case "integer":
+		f, ok := value.(float64)
+		if !ok || f != math.Trunc(f) {
+			return nil, typeError(path, schema.Type, value)
+		}
+		converted = int(f)
+	case "number":
+		f, ok := value.(float64)
+		if !ok {
+			return nil, typeError(path, schema.Type, value)
+		}
+		converted = f
+	case "boolean":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, typeError(path, schema.Type, value)
+		}
+		converted = b
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, typeError(path, schema.Type, value)
+		}
+		result := make([]interface{}, 0, len(items))
+		for i, item := range items {
+			if schema.Items == nil {
+				result = append(result, item)
+				continue
+			}
+			v, err := validateValue(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, v)
+		}
+		converted = result
+	case "object":
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, typeError(path, schema.Type, value)
+		}
+		return validateObject(schema, m, path)
+	default:
+		return nil, fmt.Errorf("%s has unsupported schema type %q", path, schema.Type)
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, converted) {
+		return nil, fmt.Errorf("%s must be one of %v, got %v", path, schema.Enum, converted)
+	}
+	return converted, nil
+}
+
+// inEnum compares through fmt so that enum values decoded from YAML or
+// written as Go ints match the converted argument
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func typeError(path, want string, got interface{}) error {
+	return fmt.Errorf("%s must be %s, got %T", path, want, got)
+}
+
+// describeParameters renders an action's parameters for the router prompt
+func describeParameters(schema *types.ParameterSchema) string {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		logger.Warnf("Router: Cannot describe parameters: %v", err)
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,119 @@
+package transcriptionrouter
+
+import (
+	"fmt"
+
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/internal/types"
+)
+
+// maxPlanSteps limits how many actions one utterance can run
+const maxPlanSteps = 5
+
+// PlanStep is one action of a multi-action utterance, as returned by the LLM
+type PlanStep struct {
+	Action    string                 `json:"action"`
+	Text      string                 `json:"text"`
+	Arguments map[string]interface{} `json:"arguments"`
+	// UsePreviousOutput appends the previous step's output to Text. Actions
+	// that produce no text pass on the URL or message of their result.
+	UsePreviousOutput bool `json:"use_previous_output"`
+}
+
+// StepResult reports one executed plan step
+type StepResult struct {
+	Index   int // 1-based
+	Total   int
+	Action  string
+	Success bool
+	// Output is the text the step produced, or the error message when it failed
+	Output string
+}
+
+// StepNotifier reports plan progress to the user.
+// It is implemented by the D-Bus server.
+type StepNotifier interface {
+	EmitActionStepCompleted(result StepResult) error
+}
+
+// runPlan executes the steps in order. A failing step stops the plan; the
+// remaining steps are not run.
+func (r *Router) runPlan(ec *types.ExecutionContext, steps []PlanStep) error {
+	// Invalid plans fail before anything runs
+	actions, err := r.planActions(steps)
+	if err != nil {
+		return err
+	}
+
+	notifier, _ := state.Get().GetDBusServer().(StepNotifier)
+	output := ""
+	for i, step := range steps {
+		text := step.Text
+		if step.UsePreviousOutput {
+			text = withPreviousOutput(text, output)
+		}
+
+		logger.Infof("Router: [%s] Plan step %d/%d: %s with text: %s", ec.RequestID, i+1, len(steps), step.Action, text)
+		actionResult, err := r.execute(ec, actions[i], text, step.Arguments)
+
+		result := StepResult{Index: i + 1, Total: len(steps), Action: step.Action, Success: err == nil, Output: actionResult.Text}
+		if err != nil {
+			result.Output = err.Error()
+		}
+		if notifier != nil {
+			if nerr := notifier.EmitActionStepCompleted(result); nerr != nil {
+				logger.Warnf("Router: Failed to report plan step %d: %v", i+1, nerr)
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("plan step %d/%d (%s) failed, skipping the rest: %w", i+1, len(steps), step.Action, err)
+		}
+		r.remember(text, step.Action)
+		output = stepOutput(actionResult)
+		// Text nobody consumes is pasted like the output of a single action
+		if i+1 == len(steps) || !steps[i+1].UsePreviousOutput {
+			r.deliverText(ec, actionResult)
+		}
+	}
+	return nil
+}
+
+// stepOutput is what a step passes to the next one: its text, or the URL
+// (e.g. of a created ticket) or message of its result
+func stepOutput(result *types.ActionResult) string {
+	switch {
+	case result.Text != "":
+		return result.Text
+	case result.URL != "":
+		return result.URL
+	}
+	return result.Message
+}
+
+// planActions resolves the action of every step. It fails for plans with too
+// many steps or a step using an unknown action.
+func (r *Router) planActions(steps []PlanStep) ([]types.PluginAction, error) {
+	if len(steps) > maxPlanSteps {
+		return nil, fmt.Errorf("plan has %d steps, at most %d are allowed", len(steps), maxPlanSteps)
+	}
+	actions := make([]types.PluginAction, len(steps))
+	for i, step := range steps {
+		if actions[i] = r.findAction(step.Action); actions[i] == nil {
+			return nil, fmt.Errorf("plan step %d uses unknown action %s", i+1, step.Action)
+		}
+	}
+	return actions, nil
+}
+
+// withPreviousOutput feeds the previous step's output into the step text
+func withPreviousOutput(text, output string) string {
+	switch {
+	case output == "":
+		return text
+	case text == "":
+		return output
+	}
+	return text + "\n\n" + output
+}
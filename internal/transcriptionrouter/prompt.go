@@ -0,0 +1,101 @@
+package transcriptionrouter
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/dooshek/voicify/internal/fileops"
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/state"
+)
+
+// promptFiles are the built-in prompts, used when the user has no copy
+//
+//go:embed prompts/*.md
+var promptFiles embed.FS
+
+// routerPromptName is the base name of the router prompt files
+const routerPromptName = "router"
+
+// cachePromptTemplate parses the router prompt; see PromptData for its
+// variables. The first of these that exists is used:
+//
+//  1. llm.router.prompt_file
+//  2. router.<language>.md in the prompts directory (~/.config/voicify/resources/prompts)
+//  3. router.md in the prompts directory
+//  4. the built-in router.<language>.md, then router.md
+//
+// <language> is llm.transcription.language, e.g. "pl". Voicify never writes
+// to the prompts directory, so a user's copy is kept across upgrades.
+func (r *Router) cachePromptTemplate() error {
+	cfg := state.Get().GetConfig()
+	name, data, err := findPrompt(routerPromptName, cfg.LLM.Transcription.Language, cfg.LLM.Router.PromptFile)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(filepath.Base(name)).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return fmt.Errorf("prompt template %s is invalid: %w", name, err)
+	}
+	// Unknown variables only fail on execution; catch them before routing does
+	if err := tmpl.Execute(io.Discard, PromptData{}); err != nil {
+		return fmt.Errorf("prompt template %s is invalid: %w", name, err)
+	}
+	logger.Debugf("Router: Using prompt template %s", name)
+	r.prompt = tmpl
+	r.promptSource = name
+	return nil
+}
+
+// findPrompt returns the source and contents of the named prompt
+func findPrompt(base, language, override string) (string, []byte, error) {
+	if override != "" {
+		data, err := os.ReadFile(override)
+		if err != nil {
+			return "", nil, fmt.Errorf("prompt template read failed: %w", err)
+		}
+		return override, data, nil
+	}
+
+	names := promptNames(base, language)
+	if fileOps, err := fileops.NewDefaultFileOps(); err != nil {
+		logger.Warnf("Router: Cannot locate the prompts directory, using built-in prompts: %v", err)
+	} else {
+		for _, name := range names {
+			path := filepath.Join(fileOps.GetPromptsDir(), name)
+			data, err := os.ReadFile(path)
+			if err == nil {
+				return path, data, nil
+			}
+			if !os.IsNotExist(err) {
+				return "", nil, fmt.Errorf("prompt template read failed: %w", err)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if data, err := promptFiles.ReadFile("prompts/" + name); err == nil {
+			return "built-in " + name, data, nil
+		}
+	}
+	return "", nil, fmt.Errorf("no %s prompt found", base)
+}
+
+// promptNames lists the file names of a prompt, most specific first: for
+// language "pt-BR" these are router.pt-br.md, router.pt.md and router.md
+func promptNames(base, language string) []string {
+	var names []string
+	language = strings.ToLower(strings.ReplaceAll(language, "_", "-"))
+	if language != "" {
+		names = append(names, base+"."+language+".md")
+		if short, _, found := strings.Cut(language, "-"); found {
+			names = append(names, base+"."+short+".md")
+		}
+	}
+	return append(names, base+".md")
+}
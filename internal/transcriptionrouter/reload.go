@@ -0,0 +1,128 @@
+package transcriptionrouter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dooshek/voicify/internal/config"
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/state"
+	"github.com/dooshek/voicify/internal/types"
+)
+
+// configPollInterval is how often WatchConfig checks the config file
+const configPollInterval = 2 * time.Second
+
+// ConfigOverrider re-applies runtime settings, such as the transcription
+// model chosen in the extension, to a reloaded configuration.
+// It is implemented by the D-Bus server.
+type ConfigOverrider interface {
+	OverrideConfig(cfg *types.Config)
+}
+
+// Reload re-reads the config file and replaces the global router with one
+// built from it: LLM provider, plugins, actions, rules and prompt. Requests
+// in flight finish on the previous router and recordings are not touched.
+// When the new router cannot be set up, the previous configuration and
+// router stay in place.
+func Reload() error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg == nil {
+		return fmt.Errorf("config file not found")
+	}
+	if overrider, ok := state.Get().GetDBusServer().(ConfigOverrider); ok {
+		overrider.OverrideConfig(cfg)
+	}
+
+	routerMu.Lock()
+	defer routerMu.Unlock()
+
+	// The provider and plugins read the global config while they are created
+	previousCfg := state.Get().GetConfig()
+	state.Get().SetConfig(cfg)
+	router, err := createNewRouter()
+	if err != nil {
+		router.baseCancel()
+		state.Get().SetConfig(previousCfg)
+		return fmt.Errorf("keeping the previous router: %w", err)
+	}
+
+	if previous, ok := state.Get().GetRouter().(*Router); ok {
+		router.inherit(previous)
+	}
+	state.Get().SetRouter(router)
+	logger.Infof("Router: Reloaded with %d actions and %d rules", len(router.actions), len(router.rules))
+	return nil
+}
+
+// inherit carries over what must survive a reload: the requests in flight,
+// so Cancel and Shutdown still reach them, the routing history and a choice
+// the user has not made yet
+func (r *Router) inherit(previous *Router) {
+	r.baseCancel()
+	r.baseCtx, r.baseCancel = previous.baseCtx, previous.baseCancel
+	r.inflight = previous.inflight
+
+	recent := previous.recentRoutes()
+	previous.mu.Lock()
+	pending := previous.pending
+	previous.mu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.historySize > 0 {
+		if len(recent) > r.historySize {
+			recent = recent[len(recent)-r.historySize:]
+		}
+		r.recent = recent
+	}
+	r.pending = pending
+}
+
+// WatchConfig reloads the router whenever the config file changes, until
+// ctx is done. Changes are detected by polling the file's modification time
+// and size; a half-written file fails to load and is retried on the next
+// change.
+func WatchConfig(ctx context.Context) {
+	path, err := config.FilePath()
+	if err != nil {
+		logger.Warnf("Router: Not watching the config file: %v", err)
+		return
+	}
+	logger.Debugf("Router: Watching %s for changes", path)
+
+	last := fileStamp(path)
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		stamp := fileStamp(path)
+		if stamp == last {
+			continue
+		}
+		last = stamp
+		logger.Infof("Router: %s changed, reloading", path)
+		if err := Reload(); err != nil {
+			logger.Error("Failed to reload the router", err)
+		}
+	}
+}
+
+// fileStamp identifies a version of the file; "" when it does not exist
+func fileStamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+}
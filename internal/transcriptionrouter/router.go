@@ -2,67 +2,67 @@ package transcriptionrouter
 
 import (
 	"context"
-	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/fs"
-	"os"
-	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"text/template"
 
 	llm "github.com/dooshek/voicify/internal/llm"
 	"github.com/dooshek/voicify/internal/logger"
 	"github.com/dooshek/voicify/internal/plugin"
 	"github.com/dooshek/voicify/internal/state"
 	"github.com/dooshek/voicify/internal/types"
+	"github.com/dooshek/voicify/pkg/pluginapi"
 )
 
-//go:embed prompts/*.md
-var promptFiles embed.FS
-
-func initializeResources() error {
-	// Create prompts directory in current working directory
-	promptsDir := "./prompts"
-	if err := os.MkdirAll(promptsDir, 0o755); err != nil {
-		return fmt.Errorf("failed to create prompts directory: %w", err)
-	}
-
-	return fs.WalkDir(promptFiles, "prompts", func(path string, d fs.DirEntry, err error) error {
-		if err != nil || d.IsDir() || filepath.Ext(d.Name()) != ".md" {
-			return err
-		}
-
-		data, err := promptFiles.ReadFile(path)
-		if err != nil {
-			return fmt.Errorf("error reading %s: %w", path, err)
-		}
-
-		destPath := filepath.Join(promptsDir, filepath.Base(path))
-		return os.WriteFile(destPath, data, 0o644)
-	})
-}
-
-func init() {
-	if err := initializeResources(); err != nil {
-		logger.Errorf("Resource initialization failed: %v", err)
-	}
-}
-
 type llmResponse struct {
-	Action                      string `json:"action"`
-	TranscriptionWithoutCommand string `json:"transcription_without_command"`
+	Thoughts                    string      `json:"thoughts"`
+	Action                      string      `json:"action"`
+	Confidence                  *float64    `json:"confidence"`
+	TranscriptionWithoutCommand string      `json:"transcription_without_command"`
+	Alternatives                []Candidate `json:"alternatives"`
+	// Arguments are filled for actions declaring Parameters
+	Arguments map[string]interface{} `json:"arguments"`
+	// Steps are set when the utterance asks for several actions in a row
+	Steps []PlanStep `json:"steps"`
 }
 
 type Router struct {
-	actions     []types.PluginAction
-	llmProvider llm.Provider
-	promptCache string
-	pluginMgr   *plugin.Manager
+	actions         []types.PluginAction
+	llmProvider     llm.Provider
+	prompt          *template.Template
+	promptSource    string
+	pluginMgr       *plugin.Manager
+	rules           []rule
+	commandPrefixes []string
+	minConfidence   float64
+	lowConfidence   string
+	historySize     int
+	actionTimeout   int
+	actionTimeouts  map[string]int
+	// baseCtx is the parent of every routing request; Shutdown cancels it
+	baseCtx    context.Context
+	baseCancel context.CancelFunc
+	// inflight are the routing requests in progress, shared across reloads
+	inflight *requestSet
+	// recent are the last routed utterances, shown to the LLM as context
+	recent []RecentRoute
+	// pending is the last ambiguous transcription waiting for the user's choice
+	pending *pendingChoice
+	mu      sync.Mutex
 }
 
+// routerMu serializes creating and reloading the global router
+var routerMu sync.Mutex
+
 // GetOrCreateGlobalRouter returns existing global router or creates new one
 func GetOrCreateGlobalRouter() *Router {
+	routerMu.Lock()
+	defer routerMu.Unlock()
+
 	// Check if global router exists
 	if existingRouter := state.Get().GetRouter(); existingRouter != nil {
 		if router, ok := existingRouter.(*Router); ok {
@@ -71,25 +71,29 @@ func GetOrCreateGlobalRouter() *Router {
 		}
 	}
 
-	// Create new router
+	// Create new router; its problems are logged and it works without the failed parts
 	logger.Debug("Router: Creating new global router")
-	router := createNewRouter()
+	router, _ := createNewRouter()
 	state.Get().SetRouter(router)
 	return router
 }
 
-func createNewRouter() *Router {
+// createNewRouter builds a router from the current configuration. The
+// returned error lists what could not be set up; the router is usable anyway.
+func createNewRouter() (*Router, error) {
 	routerProvider := state.Get().GetRouterProvider()
 	logger.Debugf("Router: Initializing with provider: '%s'", routerProvider)
 
 	var provider llm.Provider
 	var providerErr error
+	var problems []error
 
 	// Only try to create LLM provider if router provider is configured
 	if string(routerProvider) != "" {
 		provider, providerErr = llm.NewProvider(routerProvider)
 		if providerErr != nil {
 			logger.Warnf("Router: Failed to create LLM provider for '%s': %v", routerProvider, providerErr)
+			problems = append(problems, fmt.Errorf("LLM provider %s: %w", routerProvider, providerErr))
 		} else {
 			logger.Debugf("Router: LLM provider created successfully for '%s'", routerProvider)
 		}
@@ -108,6 +112,7 @@ func createNewRouter() *Router {
 	logger.Debugf("Router: Attempting to register plugins...")
 	if err := plugin.RegisterAllPlugins(pluginMgr); err != nil {
 		logger.Errorf("Failed to register plugins: %v", err)
+		problems = append(problems, fmt.Errorf("plugins: %w", err))
 	} else {
 		logger.Debugf("Router: Successfully registered plugins")
 		// Get actions from all plugins (use empty transcription for initialization)
@@ -126,22 +131,45 @@ func createNewRouter() *Router {
 		logger.Infof("Router: No plugin actions found - only basic routing will be available")
 	}
 
+	routerCfg := state.Get().GetConfig().LLM.Router
 	r := &Router{
-		llmProvider: provider,
-		actions:     actions,
-		pluginMgr:   pluginMgr,
+		llmProvider:     provider,
+		actions:         actions,
+		pluginMgr:       pluginMgr,
+		rules:           compileRules(routerCfg.Rules),
+		commandPrefixes: routerCfg.CommandPrefixes,
+		minConfidence:   routerCfg.MinConfidence,
+		lowConfidence:   routerCfg.LowConfidence,
+		historySize:     routerCfg.HistorySize,
+		actionTimeout:   routerCfg.ActionTimeout,
+		actionTimeouts:  routerCfg.ActionTimeouts,
+		inflight:        newRequestSet(),
+	}
+	r.baseCtx, r.baseCancel = context.WithCancel(context.Background())
+	if r.minConfidence == 0 {
+		r.minConfidence = defaultMinConfidence
+	}
+	if r.historySize == 0 {
+		r.historySize = defaultHistorySize
 	}
+	for _, rl := range r.rules {
+		if r.findAction(rl.action) == nil {
+			logger.Warnf("Router: Rule %s refers to unknown action %s", rl.name, rl.action)
+		}
+	}
+	logger.Debugf("Router: Loaded %d routing rules", len(r.rules))
 
 	// Only cache prompt template if we have a working LLM provider
 	if provider != nil {
 		if err := r.cachePromptTemplate(); err != nil {
 			logger.Error("Failed to cache prompt template", err)
+			problems = append(problems, err)
 		}
 	} else {
 		logger.Debugf("Router: Skipping prompt template caching - no LLM provider available")
 	}
 
-	return r
+	return r, errors.Join(problems...)
 }
 
 // New returns the global router (for backward compatibility)
@@ -149,16 +177,6 @@ func New(transcription string) *Router {
 	return GetOrCreateGlobalRouter()
 }
 
-func (r *Router) cachePromptTemplate() error {
-	promptPath := "./prompts/router.md"
-	data, err := os.ReadFile(promptPath)
-	if err != nil {
-		return fmt.Errorf("prompt template read failed: %w", err)
-	}
-	r.promptCache = string(data)
-	return nil
-}
-
 func (r *Router) findAction(actionName string) types.PluginAction {
 	logger.Debugf("Looking for action with name: %s", actionName)
 	for _, a := range r.actions {
@@ -173,49 +191,107 @@ func (r *Router) findAction(actionName string) types.PluginAction {
 	return nil
 }
 
+// routeOptions adjust how a transcription is routed
+type routeOptions struct {
+	// typed is set when the transcription is already in the focused window
+	typed bool
+	// pasteUnhandled pastes a transcription no action handles
+	pasteUnhandled bool
+	// explained, when set, supplies the LLM decision
+	explained *Explanation
+}
+
+// Route runs the actions matching the transcription; the default action pastes it
 func (r *Router) Route(transcription string) error {
-	logger.Debugf("Router: Starting routing for transcription: %s", transcription)
+	return r.route(transcription, routeOptions{})
+}
 
-	// First pass: check which actions want to skip default action
-	skipDefaultAction := false
-	for _, a := range r.actions {
-		if a.GetMetadata().LLMRouterPrompt == nil || *a.GetMetadata().LLMRouterPrompt == "" {
-			meta := a.GetMetadata()
-			if meta.SkipDefaultAction {
-				skipDefaultAction = true
-				logger.Debugf("Router: Action %s will skip default action", meta.Name)
-			}
-		}
+// RouteTyped routes a transcription that was already typed into the focused
+// window, e.g. by live typing. It runs the same actions as Route, but the
+// default action does not paste the text again.
+func (r *Router) RouteTyped(transcription string) error {
+	return r.route(transcription, routeOptions{typed: true})
+}
+
+// RouteDictation routes a transcription dictated into the focused window,
+// e.g. in realtime mode. It runs the same actions as Route and pastes the
+// transcription when none of them handles it.
+func (r *Router) RouteDictation(transcription string) error {
+	return r.route(transcription, routeOptions{pasteUnhandled: true})
+}
+
+// Execute routes the transcription of an explanation. The LLM is not asked
+// again: its explained decision is executed, so the actions that run are the
+// ones the explanation showed.
+func (r *Router) Execute(e *Explanation) error {
+	return r.route(e.Transcription, routeOptions{explained: e})
+}
+
+// route runs the routing steps
+func (r *Router) route(transcription string, opts routeOptions) error {
+	explained := opts.explained
+	ec, finish := r.newExecution(transcription, opts.typed)
+	defer finish()
+	logger.Debugf("Router: [%s] Starting routing for transcription: %s", ec.RequestID, transcription)
+
+	// A configured prefix ("komenda ...") marks an explicit command that skips the non-LLM actions
+	original := transcription
+	transcription, isCommand := stripCommandPrefix(r.commandPrefixes, transcription)
+	if isCommand {
+		logger.Debugf("Router: Explicit command: %s", transcription)
+	}
+
+	// Deterministic rules are checked before anything else and skip the LLM
+	if match := matchRules(r.rules, transcription); match != nil {
+		return r.executeRule(ec, match)
 	}
 
-	// Second pass: execute actions (skip default if needed)
+	if isCommand {
+		// The prefix may have been ordinary speech, so a command no action handles is pasted
+		return r.routeWithLLM(ec, transcription, original, explained)
+	}
+
+	// Execute non-LLM actions in priority order
 	nonLLMActionsExecuted := 0
 	actionExecutedWithSkipDefault := false
-	for _, a := range r.actions {
-		if a.GetMetadata().LLMRouterPrompt == nil || *a.GetMetadata().LLMRouterPrompt == "" {
-			meta := a.GetMetadata()
+	handledBy := ""
+	for _, st := range r.nonLLMSteps() {
+		meta := st.action.GetMetadata()
+		if st.skipReason != "" {
+			logger.Debugf("Router: Skipping action %s - %s", meta.Name, st.skipReason)
+			continue
+		}
 
-			// Skip default action if any plugin has set SkipDefaultAction to true
-			if meta.Name == "default" && skipDefaultAction {
-				logger.Debug("Router: Skipping default action - another plugin already handled it")
+		logger.Debugf("Router: Executing non-LLM action: %s", meta.Name)
+		nonLLMActionsExecuted++
+
+		result, err := r.execute(ec, st.action, transcription, nil)
+		if err != nil {
+			if errors.Is(err, pluginapi.ErrActionSkipped) {
+				logger.Debugf("Router: Action %s does not apply to this transcription", meta.Name)
 				continue
 			}
-
-			logger.Debugf("Router: Executing non-LLM action: %s", meta.Name)
-			nonLLMActionsExecuted++
-
-			if err := a.Execute(transcription); err != nil {
-				logger.Errorf("Action %s failed to execute", err, meta.Name)
-			} else {
-				// Check if this action has SkipDefaultAction set to true
-				if meta.SkipDefaultAction {
-					actionExecutedWithSkipDefault = true
-					logger.Debugf("Router: Action %s executed with SkipDefaultAction=true - ending routing", meta.Name)
-				}
+			if ec.Context.Err() != nil {
+				// Cancelled by the user or shutdown - run nothing else
+				return err
+			}
+			logger.Errorf("Action %s failed to execute", err, meta.Name)
+		} else {
+			r.deliverText(ec, result)
+			handledBy = meta.Name
+			// Check if this action has SkipDefaultAction set to true
+			if meta.SkipDefaultAction {
+				actionExecutedWithSkipDefault = true
+				logger.Debugf("Router: Action %s executed with SkipDefaultAction=true - ending routing", meta.Name)
+				// Later actions would insert the transcription a second time
+				break
 			}
 		}
 	}
 	logger.Debugf("Router: Executed %d non-LLM actions", nonLLMActionsExecuted)
+	if handledBy != "" {
+		r.remember(transcription, handledBy)
+	}
 
 	// If any action with SkipDefaultAction was executed, end routing here
 	if actionExecutedWithSkipDefault {
@@ -223,74 +299,183 @@ func (r *Router) Route(transcription string) error {
 		return nil
 	}
 
-	// Check if there are any actions with LLMRouterPrompt
-	hasLLMActions := false
-	llmActionCount := 0
+	fallback := ""
+	if opts.pasteUnhandled {
+		fallback = transcription
+	}
+	return r.routeWithLLM(ec, transcription, fallback, explained)
+}
+
+// routeStep is a non-LLM action in execution order
+type routeStep struct {
+	action     types.PluginAction
+	skipReason string // set when Route does not run the action
+}
+
+// nonLLMSteps lists the actions Route runs before consulting the LLM. The
+// default action is skipped when any action handles the text on its own.
+func (r *Router) nonLLMSteps() []routeStep {
+	skipDefaultAction := false
 	for _, a := range r.actions {
-		if meta := a.GetMetadata(); meta.LLMRouterPrompt != nil && *meta.LLMRouterPrompt != "" {
-			hasLLMActions = true
-			llmActionCount++
+		if meta := a.GetMetadata(); !isLLMAction(meta) && meta.SkipDefaultAction {
+			skipDefaultAction = true
+			logger.Debugf("Router: Action %s will skip default action", meta.Name)
 		}
 	}
 
-	// Skip LLM analysis if there are no actions with LLMRouterPrompt
-	if !hasLLMActions {
-		logger.Debugf("Router: Skipping LLM analysis - no actions with LLMRouterPrompt defined")
-		return nil
+	var steps []routeStep
+	for _, a := range r.actions {
+		meta := a.GetMetadata()
+		if isLLMAction(meta) {
+			continue
+		}
+		st := routeStep{action: a}
+		if meta.Name == defaultActionName && skipDefaultAction {
+			st.skipReason = "another action has SkipDefaultAction"
+		}
+		steps = append(steps, st)
 	}
+	return steps
+}
+
+// isLLMAction reports whether the action is only selected by the LLM
+func isLLMAction(meta types.ActionMetadata) bool {
+	return meta.LLMRouterPrompt != nil && *meta.LLMRouterPrompt != ""
+}
 
-	// Skip LLM analysis if no LLM provider is available
-	if r.llmProvider == nil {
-		logger.Infof("Router: Skipping LLM analysis - no LLM provider configured (found %d actions requiring LLM)", llmActionCount)
+// executeRule runs the action selected by a routing rule
+func (r *Router) executeRule(ec *types.ExecutionContext, match *ruleMatch) error {
+	action := r.findAction(match.rule.action)
+	if action == nil {
+		logger.Warnf("Router: Rule %s matched but action %s does not exist", match.rule.name, match.rule.action)
 		return nil
 	}
 
-	logger.Debugf("Router: Starting LLM analysis with %d LLM-enabled actions", llmActionCount)
-	llmResp, err := r.analyzeWithLLM(transcription)
+	logger.Infof("Router: Rule %s selected action %s with text: %s", match.rule.name, match.rule.action, match.text)
+	result, err := r.execute(ec, action, match.text, nil)
+	if err != nil {
+		logger.Errorf("Rule-selected action %s failed", err, match.rule.action)
+		return err
+	}
+	r.deliverText(ec, result)
+	r.remember(match.text, match.rule.action)
+	return nil
+}
+
+// routeWithLLM asks the LLM which LLM-routed action fits the transcription and
+// runs it. When no action runs, a non-empty fallback is pasted instead.
+func (r *Router) routeWithLLM(ec *types.ExecutionContext, transcription, fallback string, explained *Explanation) error {
+	unhandled := func() error {
+		if fallback == "" {
+			return nil
+		}
+		logger.Infof("Router: No action handles the transcription - pasting it")
+		return r.pasteFallback(ec, fallback)
+	}
+
+	if reason := r.llmSkipReason(); reason != "" {
+		logger.Infof("Router: Skipping LLM analysis - %s", reason)
+		return unhandled()
+	}
+
+	// An unsure LLM gets what the user said pasted, command prefix included
+	said := transcription
+	if fallback != "" {
+		said = fallback
+	}
+
+	logger.Debug("Router: Starting LLM analysis")
+	llmResp, err := r.decide(ec.Context, transcription, explained)
 	if err != nil {
+		if ec.Context.Err() != nil {
+			return ec.Context.Err()
+		}
 		logger.Error("LLM analysis failed", err)
-		return nil
+		return unhandled()
+	}
+
+	logger.Infof("Router: LLM chose %s (confidence %s): %s",
+		llmResp.Action, formatConfidence(llmResp.Confidence), llmResp.Thoughts)
+
+	// A plan is checked step by step; its top-level action is only informative
+	if len(llmResp.Steps) > 1 {
+		if r.belowThreshold(llmResp.Confidence) {
+			return r.handleLowConfidence(ec, said, llmResp)
+		}
+		logger.Infof("Router: Running a plan of %d steps", len(llmResp.Steps))
+		return r.runPlan(ec, llmResp.Steps)
 	}
 
-	logger.Debugf("LLM suggested action: %s", llmResp.Action)
 	if action := r.findAction(llmResp.Action); action != nil {
+		if r.belowThreshold(llmResp.Confidence) {
+			return r.handleLowConfidence(ec, said, llmResp)
+		}
+
 		logger.Debugf("Executing LLM-selected action: %s with transcription: %s",
 			action.GetMetadata().Name, llmResp.TranscriptionWithoutCommand)
-		err := action.Execute(llmResp.TranscriptionWithoutCommand)
+		result, err := r.execute(ec, action, llmResp.TranscriptionWithoutCommand, llmResp.Arguments)
 		if err != nil {
 			logger.Errorf("LLM-selected action %s failed", err, action.GetMetadata().Name)
 		} else {
 			logger.Debugf("Action %s: LLM-selected action completed successfully", action.GetMetadata().Name)
+			r.deliverText(ec, result)
+			r.remember(transcription, action.GetMetadata().Name)
 		}
 		return err
 	}
 	logger.Debugf("No action executed for this transcription")
-	return nil
+	return unhandled()
 }
 
-func (r *Router) analyzeWithLLM(transcription string) (*llmResponse, error) {
-	logger.Debugf("Router: Starting LLM analysis for transcription: %s", transcription)
-
-	actionsDoc := strings.Builder{}
+// decide asks the LLM about the transcription, or returns the decision an
+// explanation already got
+func (r *Router) decide(ctx context.Context, transcription string, explained *Explanation) (*llmResponse, error) {
+	switch {
+	case explained == nil:
+		return r.analyzeWithLLM(ctx, transcription)
+	case explained.LLMError != nil:
+		return nil, explained.LLMError
+	case explained.response == nil:
+		return nil, fmt.Errorf("the explanation has no LLM decision")
+	}
+	return explained.response, nil
+}
 
-	logger.Debugf("Building LLM actions documentation with %d available actions", len(r.actions))
+// llmSkipReason explains why the LLM cannot be consulted, or returns ""
+func (r *Router) llmSkipReason() string {
+	llmActionCount := 0
 	for _, a := range r.actions {
-		if meta := a.GetMetadata(); meta.LLMRouterPrompt != nil && *meta.LLMRouterPrompt != "" {
-			logger.Debugf("Adding action to LLM prompt: %s", meta.Name)
-			actionsDoc.WriteString(fmt.Sprintf("- %s\n", *meta.LLMRouterPrompt))
+		if isLLMAction(a.GetMetadata()) {
+			llmActionCount++
 		}
 	}
 
-	prompt := fmt.Sprintf(string(r.promptCache), actionsDoc.String(), transcription)
+	switch {
+	case llmActionCount == 0:
+		return "no actions with LLMRouterPrompt defined"
+	case r.llmProvider == nil:
+		return fmt.Sprintf("no LLM provider configured (found %d actions requiring LLM)", llmActionCount)
+	}
+	return ""
+}
+
+func (r *Router) analyzeWithLLM(ctx context.Context, transcription string) (*llmResponse, error) {
+	logger.Debugf("Router: Starting LLM analysis for transcription: %s", transcription)
+
+	prompt, err := r.renderPrompt(transcription)
+	if err != nil {
+		return nil, err
+	}
 	logger.Debugf("LLM request prompt: %+v", prompt)
 
+	routerCfg := state.Get().GetConfig().LLM.Router
 	req := llm.CompletionRequest{
-		Model:       state.Get().Config.LLM.Router.Model,
+		Model:       routerCfg.Model,
 		Messages:    []llm.ChatCompletionMessage{{Role: "user", Content: prompt}},
-		Temperature: float32(state.Get().Config.LLM.Router.Temperature),
+		Temperature: float32(routerCfg.Temperature),
 	}
 
-	response, err := r.llmProvider.Completion(context.Background(), req)
+	response, err := r.llmProvider.Completion(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("LLM completion failed: %w", err)
 	}
@@ -308,3 +493,15 @@ func (r *Router) analyzeWithLLM(transcription string) (*llmResponse, error) {
 
 	return &llmResp, nil
 }
+
+// renderPrompt fills the router prompt template for the transcription
+func (r *Router) renderPrompt(transcription string) (string, error) {
+	if r.prompt == nil {
+		return "", fmt.Errorf("router prompt template is not loaded")
+	}
+	var prompt strings.Builder
+	if err := r.prompt.Execute(&prompt, r.promptData(transcription)); err != nil {
+		return "", fmt.Errorf("router prompt rendering failed: %w", err)
+	}
+	return prompt.String(), nil
+}
@@ -0,0 +1,151 @@
+package transcriptionrouter
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/dooshek/voicify/internal/logger"
+	"github.com/dooshek/voicify/internal/types"
+)
+
+// rule is a compiled routing rule
+type rule struct {
+	name    string
+	action  string
+	prefix  []string
+	pattern *regexp.Regexp
+	text    string
+}
+
+// ruleMatch is a rule that matched a transcription and the action text it produced
+type ruleMatch struct {
+	rule *rule
+	text string
+}
+
+// compileRules compiles the configured rules. Invalid rules are logged and skipped.
+func compileRules(configured []types.RoutingRule) []rule {
+	var rules []rule
+	for i, cfg := range configured {
+		name := cfg.Name
+		if name == "" {
+			name = cfg.Action
+		}
+		if cfg.Action == "" {
+			logger.Warnf("Router: Rule %d (%s) has no action, skipping", i+1, name)
+			continue
+		}
+
+		rl := rule{name: name, action: cfg.Action, text: cfg.Text}
+		switch {
+		case cfg.Pattern != "":
+			pattern, err := regexp.Compile("(?i)" + cfg.Pattern)
+			if err != nil {
+				logger.Warnf("Router: Rule %s has an invalid pattern, skipping: %v", name, err)
+				continue
+			}
+			rl.pattern = pattern
+		case cfg.Prefix != "":
+			rl.prefix = strings.Fields(normalizeWords(cfg.Prefix))
+		default:
+			logger.Warnf("Router: Rule %s has neither prefix nor pattern, skipping", name)
+			continue
+		}
+		rules = append(rules, rl)
+	}
+	return rules
+}
+
+// matchRules returns the first rule matching the transcription
+func matchRules(rules []rule, transcription string) *ruleMatch {
+	for i := range rules {
+		rl := &rules[i]
+		if rl.pattern != nil {
+			if text, ok := rl.matchPattern(transcription); ok {
+				return &ruleMatch{rule: rl, text: text}
+			}
+			continue
+		}
+		if rest, ok := cutWordPrefix(transcription, rl.prefix); ok {
+			return &ruleMatch{rule: rl, text: rest}
+		}
+	}
+	return nil
+}
+
+// matchPattern returns the action text for a regex rule: the expanded text
+// template, the "text" group, or the whole transcription
+func (rl *rule) matchPattern(transcription string) (string, bool) {
+	submatches := rl.pattern.FindStringSubmatchIndex(transcription)
+	if submatches == nil {
+		return "", false
+	}
+	if rl.text != "" {
+		expanded := rl.pattern.ExpandString(nil, rl.text, transcription, submatches)
+		return strings.TrimSpace(string(expanded)), true
+	}
+	if group := rl.pattern.SubexpIndex("text"); group >= 0 && submatches[2*group] >= 0 {
+		return strings.TrimSpace(transcription[submatches[2*group]:submatches[2*group+1]]), true
+	}
+	return transcription, true
+}
+
+// stripCommandPrefix removes a leading command word ("komenda, utwórz zadanie")
+// and reports whether it was present. Without configured prefixes nothing is
+// a command.
+func stripCommandPrefix(prefixes []string, transcription string) (string, bool) {
+	for _, prefix := range prefixes {
+		if rest, ok := cutWordPrefix(transcription, strings.Fields(normalizeWords(prefix))); ok {
+			return rest, true
+		}
+	}
+	return transcription, false
+}
+
+// cutWordPrefix matches prefix words at the start of text, ignoring case and
+// punctuation, and returns the rest of text
+func cutWordPrefix(text string, prefix []string) (string, bool) {
+	if len(prefix) == 0 {
+		return "", false
+	}
+	pos := 0
+	for _, want := range prefix {
+		start, end := nextWord(text, pos)
+		if start < 0 || strings.ToLower(text[start:end]) != want {
+			return "", false
+		}
+		pos = end
+	}
+	rest := strings.TrimLeftFunc(text[pos:], func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+	return rest, true
+}
+
+// nextWord returns the byte span of the first word at or after pos
+func nextWord(text string, pos int) (int, int) {
+	start := -1
+	for i, r := range text[pos:] {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWordRune && start < 0 {
+			start = pos + i
+		} else if !isWordRune && start >= 0 {
+			return start, pos + i
+		}
+	}
+	if start < 0 {
+		return -1, -1
+	}
+	return start, len(text)
+}
+
+// normalizeWords lowercases text and replaces punctuation with spaces
+func normalizeWords(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, text)
+}
@@ -1,5 +1,7 @@
 package types
 
+import "context"
+
 // PluginMetadata contains information about a plugin
 type PluginMetadata struct {
 	Name        string
@@ -15,6 +17,9 @@ type ActionMetadata struct {
 	LLMRouterPrompt *string
 	SkipDefaultAction bool
 	Priority        int
+	// Parameters optionally describes structured arguments the router asks
+	// the LLM to extract; the action then receives them in ExecuteWithArgs
+	Parameters *ParameterSchema
 }
 
 // PluginAction represents an action provided by a plugin
@@ -23,6 +28,70 @@ type PluginAction interface {
 	GetMetadata() ActionMetadata
 }
 
+// ArgsAction is a PluginAction that accepts arguments validated against its
+// Parameters schema. transcription is the text without the command.
+type ArgsAction interface {
+	PluginAction
+	ExecuteWithArgs(transcription string, args Args) error
+}
+
+// OutputAction is a PluginAction that produces text, e.g. a summary. It
+// becomes the Text of the action's result.
+type OutputAction interface {
+	PluginAction
+	ExecuteWithOutput(transcription string) (string, error)
+}
+
+// ExecutionContext carries one routing request to the actions it runs
+type ExecutionContext struct {
+	// Context is cancelled when the action times out, the user cancels the
+	// recording or voicify shuts down
+	Context context.Context
+	// RequestID correlates the log lines of one routing request
+	RequestID     string
+	RecordingMode RecordingMode
+	Language      string
+	WindowTitle   string
+	WindowApp     string
+	// Args are the validated arguments for actions declaring Parameters
+	Args Args
+}
+
+// Severity tells how the user is notified about an ActionResult
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// ActionResult is what an action reports back to the user
+type ActionResult struct {
+	// Action is the name of the action; the router fills it in
+	Action   string   `json:"action"`
+	Message  string   `json:"message,omitempty"`
+	URL      string   `json:"url,omitempty"`
+	Severity Severity `json:"severity"`
+	// Text is text the action produced. The next plan step gets it when it
+	// uses the previous output; otherwise it is pasted into the focused window.
+	Text string `json:"text,omitempty"`
+}
+
+// ContextAction is a PluginAction that receives the execution context and
+// returns when ec.Context is done. The result may be nil.
+type ContextAction interface {
+	PluginAction
+	ExecuteContext(ec *ExecutionContext, transcription string) (*ActionResult, error)
+}
+
+// MatchAction is a PluginAction that may tell, without side effects, whether
+// it would handle the transcription; known is false when it cannot tell
+type MatchAction interface {
+	PluginAction
+	Handles(transcription string) (handles, known bool)
+}
+
 // VoicifyPlugin is the interface that all plugins must implement
 type VoicifyPlugin interface {
 	Initialize() error
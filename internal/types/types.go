@@ -39,10 +39,22 @@ const (
 	ProviderGroq   LLMProvider = "groq"
 )
 
+// RecordingMode tells how the current recording was started
+type RecordingMode string
+
+const (
+	RecordingModeKeyboard  RecordingMode = "keyboard"   // keyboard shortcut monitor
+	RecordingModeAutoPaste RecordingMode = "auto_paste" // transcription is pasted directly
+	RecordingModeRouter    RecordingMode = "router"     // transcription goes through the router
+	RecordingModeRealtime  RecordingMode = "realtime"   // streaming transcription
+	RecordingModeHybrid    RecordingMode = "hybrid"     // realtime preview with a batch final pass
+)
+
 type LLMConfig struct {
 	Keys          LLMKeys          `yaml:"keys"`
 	Transcription LLMTranscription `yaml:"transcription"`
 	Router        LLMRouter        `yaml:"router"`
+	Rewrite       LLMRewrite       `yaml:"rewrite"`
 }
 
 type LLMKeys struct {
@@ -51,15 +63,59 @@ type LLMKeys struct {
 }
 
 type LLMTranscription struct {
-	Provider string `yaml:"provider"`
-	Model    string `yaml:"model"`
-	Language string `yaml:"language"`
+	Provider  string                 `yaml:"provider"`
+	Model     string                 `yaml:"model"`
+	Language  string                 `yaml:"language"`
+	Streaming StreamingTranscription `yaml:"streaming"`
+}
+
+// StreamingTranscription selects the backend used for realtime transcription
+type StreamingTranscription struct {
+	Backend string `yaml:"backend"` // "openai" (default) or "generic"
+	URL     string `yaml:"url"`     // generic backend endpoint: ws://, wss://, http:// or https://
+	APIKey  string `yaml:"api_key"` // optional bearer token for the generic backend
 }
 
 type LLMRouter struct {
-	Provider    string  `yaml:"provider"`
-	Model       string  `yaml:"model"`
-	Temperature float64 `yaml:"temperature"`
+	Provider        string         `yaml:"provider"`
+	Model           string         `yaml:"model"`
+	Temperature     float64        `yaml:"temperature"`
+	Rules           []RoutingRule  `yaml:"rules"`            // deterministic rules checked before the LLM
+	CommandPrefixes []string       `yaml:"command_prefixes"` // words marking an utterance as a command, e.g. "komenda"; none by default
+	MinConfidence   float64        `yaml:"min_confidence"`   // LLM choices below it or without a confidence are not executed; 0 uses 0.6
+	LowConfidence   string         `yaml:"low_confidence"`   // "paste" (default) pastes the text, "ask" emits RoutingAmbiguous
+	PromptFile      string         `yaml:"prompt_file"`      // user router prompt (text/template); empty uses the built-in one
+	HistorySize     int            `yaml:"history_size"`     // recent utterances shown to the LLM; 0 uses 5, -1 disables
+	ActionTimeout   int            `yaml:"action_timeout"`   // seconds an action may run; 0 uses 120, -1 disables
+	ActionTimeouts  map[string]int `yaml:"action_timeouts"`  // per-action overrides of action_timeout, by action name
+}
+
+// RoutingRule maps a spoken prefix or a regex to an action without asking the LLM
+type RoutingRule struct {
+	Name    string `yaml:"name"`
+	Action  string `yaml:"action"`
+	Prefix  string `yaml:"prefix"`  // case-insensitive word prefix; the rest of the utterance is the action text
+	Pattern string `yaml:"pattern"` // case-insensitive regex; a (?P<text>...) group becomes the action text
+	Text    string `yaml:"text"`    // optional action text template using named groups, e.g. "${title}"
+}
+
+// LLMRewrite configures the optional LLM rewrite of dictated text before it is pasted
+type LLMRewrite struct {
+	Enabled     bool             `yaml:"enabled"`
+	Provider    string           `yaml:"provider"` // defaults to the router provider
+	Model       string           `yaml:"model"`    // defaults to the router model
+	Temperature float64          `yaml:"temperature"`
+	TimeoutMs   int              `yaml:"timeout_ms"` // raw text is used after this timeout (default 3000)
+	Profiles    []RewriteProfile `yaml:"profiles"`   // first matching profile wins
+}
+
+// RewriteProfile is a rewrite style selected by the focused window
+type RewriteProfile struct {
+	Name      string `yaml:"name"`
+	Title     string `yaml:"title"`      // regex matched against the focused window title
+	App       string `yaml:"app"`        // regex matched against the focused app name
+	Prompt    string `yaml:"prompt"`     // system prompt; the transcription is sent as the user message
+	TimeoutMs int    `yaml:"timeout_ms"` // overrides the default timeout
 }
 
 // TTSConfig holds configuration for Text-to-Speech
@@ -86,11 +142,58 @@ type TTSRealtimeConfig struct {
 }
 
 
+// PostProcessConfig configures text clean-up between transcription and routing
+type PostProcessConfig struct {
+	Stages       []string                 `yaml:"stages"`       // ordered stage names; empty uses the default chain
+	Replacements []Replacement            `yaml:"replacements"` // user find/replace dictionary
+	FillerWords  []string                 `yaml:"filler_words"` // extra filler words to remove
+	Apps         []PostProcessAppOverride `yaml:"apps"`         // per-application stage toggles
+	ITN          bool                     `yaml:"itn"`          // normalize spoken numbers, dates and units ("itn" stage)
+}
+
+// Replacement is a single find/replace dictionary entry (whole words, case-insensitive)
+type Replacement struct {
+	Find    string `yaml:"find"`
+	Replace string `yaml:"replace"`
+}
+
+// PostProcessAppOverride toggles stages when the focused app name contains Match
+type PostProcessAppOverride struct {
+	Match   string   `yaml:"match"`
+	Enable  []string `yaml:"enable"`
+	Disable []string `yaml:"disable"`
+}
+
+// CodeDictationConfig configures code dictation in editors and terminals
+type CodeDictationConfig struct {
+	Disabled      bool     `yaml:"disabled"`
+	WindowClasses []string `yaml:"window_classes"` // focused app name substrings; empty uses the built-in list
+}
+
+// RedactionConfig configures masking of personal data and secrets before text
+// is sent to an LLM or written to logs. Redaction is on unless disabled.
+type RedactionConfig struct {
+	Disabled   bool               `yaml:"disabled"`
+	Categories []string           `yaml:"categories"` // built-in detectors (email, phone, iban, card, api_key); empty enables all
+	Patterns   []RedactionPattern `yaml:"patterns"`   // user-defined detectors
+	Strict     []string           `yaml:"strict"`     // categories that make the LLM request fail instead of being masked
+	KeepLogs   bool               `yaml:"keep_logs"`  // write logs without masking
+}
+
+// RedactionPattern is a user-defined detector; Name is its category
+type RedactionPattern struct {
+	Name  string `yaml:"name"`
+	Regex string `yaml:"regex"`
+}
+
 type Config struct {
-	RecordKey KeyBinding    `yaml:"record_key"`
-	LLM       LLMConfig     `yaml:"llm"`
-	TTS       TTSConfig     `yaml:"tts"`
-	Ydotool   YdotoolConfig `yaml:"ydotool"`
+	RecordKey     KeyBinding          `yaml:"record_key"`
+	LLM           LLMConfig           `yaml:"llm"`
+	TTS           TTSConfig           `yaml:"tts"`
+	Ydotool       YdotoolConfig       `yaml:"ydotool"`
+	PostProcess   PostProcessConfig   `yaml:"post_process"`
+	CodeDictation CodeDictationConfig `yaml:"code_dictation"`
+	Redaction     RedactionConfig     `yaml:"redaction"`
 }
 
 func (c *Config) GetYdotoolConfig() YdotoolConfig {
@@ -0,0 +1,245 @@
+package voicecommand
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dooshek/voicify/internal/fileops"
+	"github.com/dooshek/voicify/internal/logger"
+	"gopkg.in/yaml.v3"
+)
+
+const grammarFilename = "voice_commands.yaml"
+
+// Action is a voice command recognized inside dictated text
+type Action string
+
+const (
+	ActionStopRecording      Action = "stop_recording"
+	ActionCancelThat         Action = "cancel_that"
+	ActionCancelAll          Action = "cancel_all"
+	ActionNewParagraph       Action = "new_paragraph"
+	ActionNewLine            Action = "new_line"
+	ActionDeleteLastSentence Action = "delete_last_sentence"
+)
+
+// Grammar maps command actions to spoken phrases per language
+type Grammar struct {
+	// Languages limits active phrase sets, e.g. ["pl", "en"]; empty means all
+	Languages []string `yaml:"languages"`
+	// Commands maps an action to phrases keyed by language code
+	Commands map[Action]map[string][]string `yaml:"commands"`
+
+	phrases []phrase
+}
+
+// Part is a piece of a parsed segment - either plain text or a command
+type Part struct {
+	Text   string
+	Action Action
+}
+
+// phrase is a compiled command phrase
+type phrase struct {
+	words  []string
+	action Action
+}
+
+// token is a word of the analyzed text with its byte span
+type token struct {
+	word       string
+	start, end int
+}
+
+// DefaultGrammar returns the built-in English and Polish command grammar
+func DefaultGrammar() *Grammar {
+	g := &Grammar{
+		Commands: map[Action]map[string][]string{
+			ActionStopRecording: {
+				"en": {"stop recording", "stop dictation", "end dictation"},
+				"pl": {"zakończ nagrywanie", "koniec nagrywania", "zakończ dyktowanie"},
+			},
+			ActionCancelThat: {
+				"en": {"cancel that"},
+				"pl": {"anuluj to"},
+			},
+			ActionCancelAll: {
+				"en": {"cancel everything"},
+				"pl": {"anuluj wszystko"},
+			},
+			ActionNewParagraph: {
+				"en": {"new paragraph"},
+				"pl": {"nowy akapit"},
+			},
+			ActionNewLine: {
+				"en": {"new line"},
+				"pl": {"nowa linia", "nowa linijka"},
+			},
+			ActionDeleteLastSentence: {
+				"en": {"delete last sentence"},
+				"pl": {"usuń ostatnie zdanie"},
+			},
+		},
+	}
+	g.compile()
+	return g
+}
+
+// LoadGrammar loads the user grammar from the config directory,
+// falling back to the built-in grammar when the file does not exist
+func LoadGrammar() (*Grammar, error) {
+	fileOps, err := fileops.NewDefaultFileOps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize file operations: %w", err)
+	}
+
+	data, err := fileOps.LoadConfig(grammarFilename)
+	if err != nil {
+		if errors.Is(err, fileops.ErrConfigNotFound) {
+			logger.Debugf("Voice commands: %s not found, using built-in grammar", grammarFilename)
+			return DefaultGrammar(), nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", grammarFilename, err)
+	}
+
+	var g Grammar
+	if err := yaml.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", grammarFilename, err)
+	}
+	g.compile()
+	logger.Debugf("Voice commands: loaded %d phrases from %s", len(g.phrases), grammarFilename)
+	return &g, nil
+}
+
+// boundaryRunes end a sentence; commands are only recognized between them
+const boundaryRunes = ".!?…;:\n"
+
+// Parse splits a completed segment into text and command parts.
+// A phrase is a command only when it is a sentence of its own: it starts the
+// segment or follows sentence punctuation, and ends the segment or is followed
+// by it, so "add a new line to the table" stays text. Command words, and
+// punctuation directly following them, are removed.
+func (g *Grammar) Parse(segment string) []Part {
+	tokens := tokenize(segment)
+
+	var parts []Part
+	textStart := 0
+	for i := 0; i < len(tokens); {
+		p := g.matchAt(tokens, i)
+		if p == nil || !atBoundary(segment, tokens, i, i+len(p.words)) {
+			i++
+			continue
+		}
+
+		if text := cleanText(segment[textStart:tokens[i].start]); text != "" {
+			parts = append(parts, Part{Text: text})
+		}
+		parts = append(parts, Part{Action: p.action})
+
+		// Skip command words and the punctuation the model put after them
+		end := tokens[i+len(p.words)-1].end
+		for end < len(segment) && strings.ContainsRune(".,!?;:", rune(segment[end])) {
+			end++
+		}
+		textStart = end
+		i += len(p.words)
+	}
+
+	if text := cleanText(segment[textStart:]); text != "" {
+		parts = append(parts, Part{Text: text})
+	}
+	return parts
+}
+
+// matchAt returns the longest phrase matching tokens starting at i
+func (g *Grammar) matchAt(tokens []token, i int) *phrase {
+	for idx := range g.phrases {
+		p := &g.phrases[idx]
+		if i+len(p.words) > len(tokens) {
+			continue
+		}
+		matched := true
+		for j, w := range p.words {
+			if tokens[i+j].word != w {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return p
+		}
+	}
+	return nil
+}
+
+// atBoundary reports whether tokens[from:to] are separated from the words
+// around them by the segment edges or sentence punctuation
+func atBoundary(segment string, tokens []token, from, to int) bool {
+	if from > 0 && !strings.ContainsAny(segment[tokens[from-1].end:tokens[from].start], boundaryRunes) {
+		return false
+	}
+	if to < len(tokens) && !strings.ContainsAny(segment[tokens[to-1].end:tokens[to].start], boundaryRunes) {
+		return false
+	}
+	return true
+}
+
+// compile tokenizes phrases of active languages, longest first
+func (g *Grammar) compile() {
+	active := make(map[string]bool)
+	for _, lang := range g.Languages {
+		active[strings.ToLower(lang)] = true
+	}
+
+	g.phrases = nil
+	for action, byLanguage := range g.Commands {
+		for lang, phrases := range byLanguage {
+			if len(active) > 0 && !active[strings.ToLower(lang)] {
+				continue
+			}
+			for _, text := range phrases {
+				var words []string
+				for _, t := range tokenize(text) {
+					words = append(words, t.word)
+				}
+				if len(words) > 0 {
+					g.phrases = append(g.phrases, phrase{words: words, action: action})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(g.phrases, func(i, j int) bool {
+		return len(g.phrases[i].words) > len(g.phrases[j].words)
+	})
+}
+
+// tokenize splits text into lowercase words with their byte spans
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\''
+		if isWordRune && start < 0 {
+			start = i
+		} else if !isWordRune && start >= 0 {
+			tokens = append(tokens, token{word: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{word: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// cleanText trims whitespace and dangling separators left around removed commands
+func cleanText(text string) string {
+	text = strings.TrimSpace(text)
+	text = strings.TrimLeft(text, ".,;: ")
+	text = strings.TrimRight(text, ",;: ")
+	return strings.TrimSpace(text)
+}
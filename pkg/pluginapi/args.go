@@ -0,0 +1,58 @@
+package pluginapi
+
+// ParameterSchema is the subset of JSON Schema used to describe action
+// parameters: object, string, integer, number, boolean and array types,
+// with required properties and enums
+type ParameterSchema struct {
+	Type        string                      `json:"type"`
+	Description string                      `json:"description,omitempty"`
+	Properties  map[string]*ParameterSchema `json:"properties,omitempty"`
+	Required    []string                    `json:"required,omitempty"`
+	Enum        []interface{}               `json:"enum,omitempty"`
+	Items       *ParameterSchema            `json:"items,omitempty"`
+}
+
+// Args are action arguments validated against the action's ParameterSchema.
+// Integers are int, numbers float64, arrays []interface{} and objects Args.
+type Args map[string]interface{}
+
+// String returns a string argument, or "" when it is missing
+func (a Args) String(name string) string {
+	s, _ := a[name].(string)
+	return s
+}
+
+// Int returns an integer argument and whether it was given
+func (a Args) Int(name string) (int, bool) {
+	i, ok := a[name].(int)
+	return i, ok
+}
+
+// Float returns a number argument and whether it was given
+func (a Args) Float(name string) (float64, bool) {
+	switch v := a[name].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// Bool returns a boolean argument and whether it was given
+func (a Args) Bool(name string) (bool, bool) {
+	b, ok := a[name].(bool)
+	return b, ok
+}
+
+// Strings returns an array of strings, skipping other elements
+func (a Args) Strings(name string) []string {
+	items, _ := a[name].([]interface{})
+	var result []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -9,20 +9,39 @@ import (
 // DBusServer interface to avoid import cycle
 type DBusServer interface {
 	EmitRequestPaste(text string) error
+	EmitRequestDelete(chars int) error
 }
 
-// RequestPaste requests the extension to paste the given text
-// This should be called by plugins when they want to insert text into the focused window
-func RequestPaste(text string) error {
+// dbusServer returns the D-Bus server from global state
+func dbusServer() (DBusServer, error) {
 	server := state.Get().GetDBusServer()
 	if server == nil {
-		return fmt.Errorf("D-Bus server not available")
+		return nil, fmt.Errorf("D-Bus server not available")
 	}
 
 	dbusServer, ok := server.(DBusServer)
 	if !ok {
-		return fmt.Errorf("invalid D-Bus server type")
+		return nil, fmt.Errorf("invalid D-Bus server type")
 	}
+	return dbusServer, nil
+}
 
-	return dbusServer.EmitRequestPaste(text)
+// RequestPaste requests the extension to paste the given text
+// This should be called by plugins when they want to insert text into the focused window
+func RequestPaste(text string) error {
+	server, err := dbusServer()
+	if err != nil {
+		return err
+	}
+	return server.EmitRequestPaste(text)
+}
+
+// RequestDelete requests the extension to delete the given number of
+// characters before the cursor in the focused window
+func RequestDelete(chars int) error {
+	server, err := dbusServer()
+	if err != nil {
+		return err
+	}
+	return server.EmitRequestDelete(chars)
 }
@@ -1,5 +1,10 @@
 package pluginapi
 
+import (
+	"context"
+	"errors"
+)
+
 // PluginMetadata contains information about a plugin
 type PluginMetadata struct {
 	Name        string
@@ -15,6 +20,46 @@ type ActionMetadata struct {
 	LLMRouterPrompt *string
 	SkipDefaultAction bool
 	Priority        int
+	// Parameters optionally describes structured arguments the router asks
+	// the LLM to extract; the action then receives them in ExecuteWithArgs
+	Parameters *ParameterSchema
+}
+
+// ExecutionContext carries one routing request to the actions it runs
+type ExecutionContext struct {
+	// Context is cancelled when the action times out, the user cancels the
+	// recording or voicify shuts down
+	Context context.Context
+	// RequestID correlates the log lines of one routing request
+	RequestID string
+	// RecordingMode tells how the recording was started: keyboard,
+	// auto_paste, router, realtime or hybrid
+	RecordingMode string
+	Language      string
+	WindowTitle   string
+	WindowApp     string
+	// Args are the validated arguments for actions declaring Parameters
+	Args Args
+}
+
+// Severity tells how the user is notified about an ActionResult
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// ActionResult is what an action reports back to the user: a message, an
+// optional URL (e.g. of a created ticket), text to paste and a severity
+type ActionResult struct {
+	Message  string
+	URL      string
+	Severity Severity
+	// Text is text the action produced. The next plan step gets it when it
+	// uses the previous output; otherwise it is pasted into the focused window.
+	Text string
 }
 
 // PluginAction represents an action provided by a plugin
@@ -23,9 +68,43 @@ type PluginAction interface {
 	GetMetadata() ActionMetadata
 }
 
+// ArgsAction is a PluginAction that accepts arguments extracted by the router.
+// Actions without Parameters, or whose arguments fail validation, get Execute.
+type ArgsAction interface {
+	PluginAction
+	ExecuteWithArgs(transcription string, args Args) error
+}
+
+// OutputAction is a PluginAction that produces text, e.g. a summary. The
+// next plan step can use it; otherwise it is pasted.
+type OutputAction interface {
+	PluginAction
+	ExecuteWithOutput(transcription string) (string, error)
+}
+
+// ContextAction is a PluginAction that receives the execution context and
+// returns when ec.Context is done. The result may be nil.
+// Actions without it are not interrupted; the router stops waiting for them.
+type ContextAction interface {
+	PluginAction
+	ExecuteContext(ec *ExecutionContext, transcription string) (*ActionResult, error)
+}
+
+// MatchAction is a PluginAction that tells, without side effects, whether it
+// would handle the transcription. `voicify route` uses it to show where
+// routing stops.
+type MatchAction interface {
+	PluginAction
+	Handles(transcription string) bool
+}
+
 // VoicifyPlugin is the interface that all plugins must implement
 type VoicifyPlugin interface {
 	Initialize() error
 	GetMetadata() PluginMetadata
 	GetActions(transcription string) []PluginAction
 }
+
+// ErrActionSkipped is returned by actions that do not apply to the transcription.
+// The router moves on to the next action instead of treating it as a failure.
+var ErrActionSkipped = errors.New("action skipped")